@@ -1,29 +1,89 @@
+// Command migrate applies or reverts the SQL files under migrations/
+// against the database described by the same env vars cmd/server reads,
+// so ops can run schema migrations as a deploy step separate from
+// starting the app.
+//
+// Usage:
+//
+//	migrate up
+//	migrate down
+//	migrate force <version>
+//	migrate version
 package main
 
 import (
-	"log"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
 
 	"github.com/cfrs2005/ppmtest/internal/config"
 	"github.com/cfrs2005/ppmtest/internal/database"
+	"github.com/cfrs2005/ppmtest/internal/logging"
 )
 
 func main() {
-	log.Println("Running database migrations...")
+	if len(os.Args) < 2 {
+		usage()
+	}
 
 	cfg, err := config.Load()
 	if err != nil {
-		log.Fatalf("Failed to load configuration: %v", err)
+		fmt.Fprintf(os.Stderr, "failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	logger := logging.New(cfg.Logging)
+	slog.SetDefault(logger)
+
+	if err := database.Initialize(&cfg.Database); err != nil {
+		logging.Fatal(logger, "failed to connect to database", "error", err)
 	}
+	defer database.Close()
 
-	db, err := database.New(&cfg.Database)
+	sqlDB, err := database.GetDB().DB()
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		logging.Fatal(logger, "failed to get database handle", "error", err)
 	}
-	defer db.Close()
 
-	if err := db.AutoMigrate(); err != nil {
-		log.Fatalf("Failed to run migrations: %v", err)
+	migrator := database.NewMigrator(sqlDB, "migrations")
+
+	switch os.Args[1] {
+	case "up":
+		if err := migrator.Up(); err != nil {
+			logging.Fatal(logger, "migrate up failed", "error", err)
+		}
+		fmt.Println("migrations applied")
+	case "down":
+		if err := migrator.Down(); err != nil {
+			logging.Fatal(logger, "migrate down failed", "error", err)
+		}
+		fmt.Println("migrations reverted")
+	case "force":
+		if len(os.Args) < 3 {
+			usage()
+		}
+		version, err := strconv.Atoi(os.Args[2])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid version %q: %v\n", os.Args[2], err)
+			os.Exit(1)
+		}
+		if err := migrator.Force(version); err != nil {
+			logging.Fatal(logger, "migrate force failed", "error", err)
+		}
+		fmt.Printf("forced version to %d\n", version)
+	case "version":
+		version, dirty, err := migrator.Version()
+		if err != nil {
+			logging.Fatal(logger, "migrate version failed", "error", err)
+		}
+		fmt.Printf("version %d (dirty=%t)\n", version, dirty)
+	default:
+		usage()
 	}
+}
 
-	log.Println("Migrations completed successfully")
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: migrate up|down|force <version>|version")
+	os.Exit(1)
 }
@@ -0,0 +1,212 @@
+// Command mirc generates internal/router/auto from the route declarations in
+// internal/mirc. It is the code-generation half of the internal/mirc package;
+// see that package's doc comment for the annotation format. Run it via
+// `make generate` (or `go generate ./...`) after editing internal/mirc.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// route is one `mirc:route` directive attached to an interface method.
+type route struct {
+	Method     string // Go method name on the group interface, e.g. "GetByID"
+	Verb       string // HTTP verb, e.g. "GET"
+	Path       string // path relative to the group's base path
+	Middleware []string
+}
+
+// group is one `mirc:group` interface, e.g. mirc.PostAPI.
+type group struct {
+	InterfaceName string
+	Name          string
+	BasePath      string
+	Routes        []route
+}
+
+// srcDir/outDir are relative to the directory `go generate` invokes this
+// command from, which internal/mirc's go:generate directive sets to
+// internal/mirc itself.
+const (
+	srcDir  = "."
+	outDir  = "../router/auto"
+	outFile = "router_gen.go"
+)
+
+func main() {
+	groups, err := parseGroups(srcDir)
+	if err != nil {
+		log.Fatalf("mirc: failed to parse %s: %v", srcDir, err)
+	}
+
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		log.Fatalf("mirc: failed to create %s: %v", outDir, err)
+	}
+
+	out, err := render(groups)
+	if err != nil {
+		log.Fatalf("mirc: failed to render generated router: %v", err)
+	}
+
+	path := filepath.Join(outDir, outFile)
+	if err := os.WriteFile(path, out, 0o644); err != nil {
+		log.Fatalf("mirc: failed to write %s: %v", path, err)
+	}
+
+	log.Printf("mirc: wrote %s (%d groups)", path, len(groups))
+}
+
+// parseGroups reads every *.go file in dir and extracts the mirc-annotated
+// interfaces declared there.
+func parseGroups(dir string) ([]group, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, nil, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []group
+
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				genDecl, ok := decl.(*ast.GenDecl)
+				if !ok || genDecl.Tok != token.TYPE {
+					continue
+				}
+
+				for _, spec := range genDecl.Specs {
+					typeSpec, ok := spec.(*ast.TypeSpec)
+					if !ok {
+						continue
+					}
+
+					iface, ok := typeSpec.Type.(*ast.InterfaceType)
+					if !ok {
+						continue
+					}
+
+					name, base, ok := parseGroupDirective(genDecl.Doc)
+					if !ok {
+						continue
+					}
+
+					g := group{InterfaceName: typeSpec.Name.Name, Name: name, BasePath: base}
+
+					for _, field := range iface.Methods.List {
+						if len(field.Names) == 0 {
+							continue
+						}
+						r, ok := parseRouteDirective(field.Doc)
+						if !ok {
+							continue
+						}
+						r.Method = field.Names[0].Name
+						g.Routes = append(g.Routes, r)
+					}
+
+					groups = append(groups, g)
+				}
+			}
+		}
+	}
+
+	return groups, nil
+}
+
+// parseGroupDirective looks for "mirc:group <name> <basePath>" in doc.
+func parseGroupDirective(doc *ast.CommentGroup) (name, basePath string, ok bool) {
+	if doc == nil {
+		return "", "", false
+	}
+	for _, line := range strings.Split(doc.Text(), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 3 && fields[0] == "mirc:group" {
+			return fields[1], fields[2], true
+		}
+	}
+	return "", "", false
+}
+
+// parseRouteDirective looks for "mirc:route <VERB> <path> [mw1,mw2]" in doc.
+func parseRouteDirective(doc *ast.CommentGroup) (route, bool) {
+	if doc == nil {
+		return route{}, false
+	}
+	for _, line := range strings.Split(doc.Text(), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 3 && fields[0] == "mirc:route" {
+			r := route{Verb: fields[1], Path: fields[2]}
+			if len(fields) >= 4 {
+				r.Middleware = strings.Split(fields[3], ",")
+			}
+			return r, true
+		}
+	}
+	return route{}, false
+}
+
+var tmpl = template.Must(template.New("router_gen").Parse(`// Code generated by cmd/mirc from internal/mirc; DO NOT EDIT.
+
+package auto
+
+import "github.com/gin-gonic/gin"
+
+// MiddlewareResolver resolves a mirc:route middleware name (e.g. "auth",
+// "admin") to the gin.HandlerFunc that should be chained in front of the
+// endpoint. Callers supply the real implementations so this package stays
+// decoupled from internal/middleware.
+type MiddlewareResolver func(name string) gin.HandlerFunc
+{{range .}}
+// {{.InterfaceName}}Handlers binds one gin.HandlerFunc per method declared on
+// mirc.{{.InterfaceName}}.
+type {{.InterfaceName}}Handlers struct {
+{{- range .Routes}}
+	{{.Method}} gin.HandlerFunc
+{{- end}}
+}
+
+// Register{{.InterfaceName}} mounts the {{.Name}} group (base path {{.BasePath}})
+// declared by mirc.{{.InterfaceName}} onto r, resolving each route's
+// middleware chain through mw.
+func Register{{.InterfaceName}}(r *gin.Engine, h {{.InterfaceName}}Handlers, mw MiddlewareResolver) {
+	rg := r.Group("{{.BasePath}}")
+{{range .Routes}}
+	rg.Handle("{{.Verb}}", "{{.Path}}", append(middlewareChain(mw, {{printf "%#v" .Middleware}}), h.{{.Method}})...)
+{{- end}}
+}
+{{end}}
+func middlewareChain(mw MiddlewareResolver, names []string) []gin.HandlerFunc {
+	chain := make([]gin.HandlerFunc, 0, len(names))
+	for _, name := range names {
+		chain = append(chain, mw(name))
+	}
+	return chain
+}
+`))
+
+func render(groups []group) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, groups); err != nil {
+		return nil, fmt.Errorf("execute template: %w", err)
+	}
+
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated source: %w", err)
+	}
+	return formatted, nil
+}
@@ -4,55 +4,172 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
-	"ppmtest/internal/config"
-	"ppmtest/internal/database"
-	"ppmtest/internal/handlers"
-	"ppmtest/internal/repository"
-	"ppmtest/internal/router"
-	"ppmtest/internal/service"
-
+	"github.com/cfrs2005/ppmtest/internal/aiplugin"
+	"github.com/cfrs2005/ppmtest/internal/auth/jwt"
+	"github.com/cfrs2005/ppmtest/internal/config"
+	"github.com/cfrs2005/ppmtest/internal/database"
+	"github.com/cfrs2005/ppmtest/internal/glm"
+	"github.com/cfrs2005/ppmtest/internal/handlers"
+	"github.com/cfrs2005/ppmtest/internal/health"
+	"github.com/cfrs2005/ppmtest/internal/logging"
+	"github.com/cfrs2005/ppmtest/internal/metrics"
+	"github.com/cfrs2005/ppmtest/internal/oidc"
+	"github.com/cfrs2005/ppmtest/internal/ratelimit"
+	"github.com/cfrs2005/ppmtest/internal/repository"
+	"github.com/cfrs2005/ppmtest/internal/router"
+	"github.com/cfrs2005/ppmtest/internal/service"
+	"github.com/cfrs2005/ppmtest/internal/storage"
+	"github.com/cfrs2005/ppmtest/internal/tracing"
+
+	"github.com/cfrs2005/ppmtest/internal/search"
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
-	log.Println("PPM Blog System starting...")
-
 	cfg, err := config.Load()
 	if err != nil {
+		// No logger exists yet - it's built from cfg.Logging below - so this
+		// one failure still goes through the stdlib logger.
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	logger := logging.New(cfg.Logging)
+	slog.SetDefault(logger)
+
+	tracing.Configure(cfg.Tracing)
+
+	logger.Info("PPM Blog System starting...")
+
 	if err := database.Initialize(&cfg.Database); err != nil {
-		log.Fatalf("Failed to initialize database: %v", err)
+		logging.Fatal(logger, "failed to initialize database", "error", err)
 	}
 
-	if err := database.Migrate(); err != nil {
-		log.Fatalf("Failed to run migrations: %v", err)
+	// Schema migrations are no longer run here: apply migrations/ via
+	// `migrate up` (cmd/migrate) as a separate deploy step before starting
+	// this binary. EnsureFullTextIndex is kept here rather than moved into
+	// a migration file since search.NewEngine's "mysql" engine depends on
+	// it unconditionally and it's a cheap, idempotent check.
+	db := database.GetDB()
+	if err := search.EnsureFullTextIndex(db); err != nil {
+		logging.Fatal(logger, "failed to ensure full-text index", "error", err)
 	}
 
-	db := database.GetDB()
+	if err := db.Use(tracing.NewGormPlugin()); err != nil {
+		logging.Fatal(logger, "failed to register tracing GORM plugin", "error", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		logging.Fatal(logger, "failed to get database handle", "error", err)
+	}
+	metrics.RegisterDBStatsCollector(sqlDB)
+
+	healthChecker := health.NewChecker(sqlDB, cfg.GLM.APIKey != "")
 
 	userRepo := repository.NewUserRepository(db)
 	postRepo := repository.NewPostRepository(db)
 	commentRepo := repository.NewCommentRepository(db)
 	tagRepo := repository.NewTagRepository(db)
 	categoryRepo := repository.NewCategoryRepository(db)
+	oauthClientRepo := repository.NewOAuthClientRepository(db)
+	authCodeRepo := repository.NewAuthorizationCodeRepository(db)
+	mfaBackupCodeRepo := repository.NewMFABackupCodeRepository(db)
+	userIdentityRepo := repository.NewUserIdentityRepository(db)
+	attachmentRepo := repository.NewAttachmentRepository(db)
+	captchaRepo := repository.NewCaptchaRepository(db)
+	reportRepo := repository.NewReportRepository(db)
+	moderationLogRepo := repository.NewModerationLogRepository(db)
+	uow := repository.NewUnitOfWork(db)
+
+	searchEngine, err := search.NewEngine(context.Background(), cfg.Search.Engine, db, postRepo)
+	if err != nil {
+		logging.Fatal(logger, "failed to initialize search engine", "error", err)
+	}
 
-	userService := service.NewUserService(userRepo)
-	postService := service.NewPostService(postRepo)
-	commentService := service.NewCommentService(commentRepo)
+	attachmentStore, err := storage.New(storage.Config{
+		Backend:     cfg.Upload.StorageBackend,
+		LocalDir:    cfg.Upload.LocalDir,
+		S3Bucket:    cfg.Upload.S3Bucket,
+		S3Region:    cfg.Upload.S3Region,
+		S3Endpoint:  cfg.Upload.S3Endpoint,
+		S3AccessKey: cfg.Upload.S3AccessKey,
+		S3SecretKey: cfg.Upload.S3SecretKey,
+	})
+	if err != nil {
+		logging.Fatal(logger, "failed to initialize attachment storage", "error", err)
+	}
 
-	userHandler := handlers.NewUserHandler(userService)
-	postHandler := handlers.NewPostHandler(postService)
+	jwtService := jwt.NewService(
+		cfg.JWT.Secret,
+		time.Duration(cfg.JWT.ExpiryHours)*time.Hour,
+		time.Duration(cfg.JWT.RefreshExpiryHours)*time.Hour,
+		jwt.NewMemoryBlocklist(),
+	)
+
+	userService := service.NewUserService(
+		userRepo,
+		mfaBackupCodeRepo,
+		userIdentityRepo,
+		cfg.MFA,
+		oidc.EmailFilter{Aliases: cfg.OIDC.EmailAliases, DomainAliases: cfg.OIDC.DomainAliases},
+	)
+	postService := service.NewPostService(postRepo, searchEngine)
+	captchaService := service.NewCaptchaService(captchaRepo)
+
+	// glmService stays nil when GLM.APIKey isn't configured - comment spam
+	// screening in CommentService.Create is then a no-op, the same as if
+	// no GLM subsystem existed at all.
+	var glmService service.GLMService
+	if cfg.GLM.APIKey != "" {
+		glmClient, err := glm.NewClient(cfg.GLM.APIKey, cfg.GLM.BaseURL, cfg.GLM.Model, cfg.GLM.MaxTokens, cfg.GLM.Temperature)
+		if err != nil {
+			logger.Warn("glm client initialization failed, comment spam screening disabled", "error", err)
+		} else {
+			glmService = service.NewGLMService(glmClient)
+		}
+	}
+	commentService := service.NewCommentService(commentRepo, moderationLogRepo, cfg.Comment.MaxDepth, captchaService, uow, glmService, cfg.GLM.SpamConfidenceThreshold)
+	tagService := service.NewTagService(tagRepo)
+	categoryService := service.NewCategoryService(categoryRepo)
+	oauthService := service.NewOAuthService(oauthClientRepo, authCodeRepo, jwtService)
+	attachmentService := service.NewAttachmentService(attachmentRepo, attachmentStore, service.AttachmentLimits{
+		MaxUserQuotaBytes: cfg.Upload.MaxUserQuotaBytes,
+		MaxImageBytes:     cfg.Upload.MaxImageBytes,
+		MaxVideoBytes:     cfg.Upload.MaxVideoBytes,
+		MaxOtherBytes:     cfg.Upload.MaxOtherBytes,
+	})
+	reportService := service.NewReportService(reportRepo, postService, commentService)
+
+	// aiManager discovers AI provider plugins (internal/aiplugin) at
+	// startup. A missing or empty plugin directory is not fatal - the blog
+	// keeps serving with only the built-in GLM client, same as if no
+	// plugins were configured at all.
+	aiManager := aiplugin.NewManager()
+	for _, discoverErr := range aiManager.Discover(cfg.AI.PluginDir) {
+		logger.Warn("ai plugin discovery failed", "error", discoverErr)
+	}
+	defer aiManager.Shutdown()
+
+	userHandler := handlers.NewUserHandler(userService, jwtService, captchaService)
+	postHandler := handlers.NewPostHandler(postService, attachmentService)
 	commentHandler := handlers.NewCommentHandler(commentService)
+	tagHandler := handlers.NewTagHandler(tagService)
+	categoryHandler := handlers.NewCategoryHandler(categoryService)
+	oauthHandler := handlers.NewOAuthHandler(oauthService, userService)
+	attachmentHandler := handlers.NewAttachmentHandler(attachmentService)
+	captchaHandler := handlers.NewCaptchaHandler(captchaService)
+	reportHandler := handlers.NewReportHandler(reportService)
 
-	r := router.SetupRouter(userHandler, postHandler, commentHandler)
+	captchaLimiter := ratelimit.NewLimiter(cfg.Captcha.RateLimitBurst, cfg.Captcha.RateLimitPerSecond)
+
+	r := router.SetupRouter(userHandler, postHandler, commentHandler, tagHandler, categoryHandler, oauthHandler, attachmentHandler, captchaHandler, reportHandler, jwtService, captchaLimiter, healthChecker)
 
 	srv := &http.Server{
 		Addr:         ":" + cfg.Server.Port,
@@ -62,9 +179,9 @@ func main() {
 	}
 
 	go func() {
-		log.Printf("Server starting on %s", srv.Addr)
+		logger.Info("server starting", "addr", srv.Addr)
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Failed to start server: %v", err)
+			logging.Fatal(logger, "failed to start server", "error", err)
 		}
 	}()
 
@@ -72,18 +189,23 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
-	log.Println("Shutting down server...")
+	logger.Info("shutting down server...")
+
+	// Flip readiness to false before the listener stops accepting
+	// connections, so Kubernetes stops routing new traffic here while
+	// in-flight requests still get to drain during srv.Shutdown.
+	healthChecker.SetReady(false)
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.Server.ShutdownTimeout)*time.Second)
 	defer cancel()
 
 	if err := srv.Shutdown(ctx); err != nil {
-		log.Fatalf("Server forced to shutdown: %v", err)
+		logging.Fatal(logger, "server forced to shutdown", "error", err)
 	}
 
 	if err := database.Close(); err != nil {
-		log.Fatalf("Error closing database: %v", err)
+		logging.Fatal(logger, "error closing database", "error", err)
 	}
 
-	log.Println("Server exited properly")
+	logger.Info("server exited properly")
 }
@@ -0,0 +1,47 @@
+package aiplugin
+
+import "encoding/json"
+
+// handshakeMagicCookie is the first token a conforming plugin binary
+// writes to stdout, so Manager can distinguish "this is an aiplugin
+// provider" from some other executable that happens to sit in the plugin
+// directory before trusting anything else it says.
+const handshakeMagicCookie = "PPMTEST_AIPLUGIN_V1"
+
+// handshake is the JSON payload following the magic cookie on a plugin's
+// first stdout line, advertising its name and what it implements.
+type handshake struct {
+	Name         string   `json:"name"`
+	Capabilities []string `json:"capabilities"` // "completer", "embedder"
+}
+
+// request is one RPC call written to a plugin's stdin, newline-delimited
+// JSON.
+type request struct {
+	ID     uint64          `json:"id"`
+	Method string          `json:"method"` // "complete" or "embed"
+	Params json.RawMessage `json:"params"`
+}
+
+// response is one RPC reply read from a plugin's stdout.
+type response struct {
+	ID     uint64          `json:"id"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+type completeParams struct {
+	Prompt string `json:"prompt"`
+}
+
+type completeResult struct {
+	Text string `json:"text"`
+}
+
+type embedParams struct {
+	Text string `json:"text"`
+}
+
+type embedResult struct {
+	Vector []float64 `json:"vector"`
+}
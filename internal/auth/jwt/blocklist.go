@@ -0,0 +1,48 @@
+package jwt
+
+import (
+	"sync"
+	"time"
+)
+
+// Blocklist tracks revoked token IDs (jti) so a logged-out or rotated token
+// is rejected even though its signature and expiry are still valid.
+type Blocklist interface {
+	Revoke(jti string, until time.Time)
+	IsRevoked(jti string) bool
+}
+
+// memoryBlocklist is the in-memory Blocklist used when no persistent store
+// is configured. Entries are dropped lazily once they pass their own
+// expiry, since an expired token is already rejected by decode.
+type memoryBlocklist struct {
+	mu      sync.Mutex
+	revoked map[string]time.Time
+}
+
+func NewMemoryBlocklist() Blocklist {
+	return &memoryBlocklist{
+		revoked: make(map[string]time.Time),
+	}
+}
+
+func (b *memoryBlocklist) Revoke(jti string, until time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.revoked[jti] = until
+}
+
+func (b *memoryBlocklist) IsRevoked(jti string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	until, ok := b.revoked[jti]
+	if !ok {
+		return false
+	}
+	if time.Now().After(until) {
+		delete(b.revoked, jti)
+		return false
+	}
+	return true
+}
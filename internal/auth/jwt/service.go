@@ -0,0 +1,195 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// Service issues and verifies token pairs for a single HS256 secret. It also
+// enforces per-user invalidation (e.g. on password change) by rejecting any
+// token issued before the user's epoch was last bumped.
+type Service struct {
+	secret     []byte
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+	blocklist  Blocklist
+
+	mu    sync.Mutex
+	epoch map[uint]time.Time
+}
+
+func NewService(secret string, accessTTL, refreshTTL time.Duration, blocklist Blocklist) *Service {
+	if blocklist == nil {
+		blocklist = NewMemoryBlocklist()
+	}
+	return &Service{
+		secret:     []byte(secret),
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+		blocklist:  blocklist,
+		epoch:      make(map[uint]time.Time),
+	}
+}
+
+func newJTI() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// IssueTokenPair mints a fresh access token and refresh token for userID,
+// each with its own jti so either can be revoked independently.
+func (s *Service) IssueTokenPair(userID uint, role string) (access, refresh string, err error) {
+	return s.IssueClientTokenPair(userID, role, "", "")
+}
+
+// IssueClientTokenPair is IssueTokenPair with an OAuth2 client ID and scope
+// attached to both tokens, for the authorization-code and refresh_token
+// grants in internal/service/oauth.go.
+func (s *Service) IssueClientTokenPair(userID uint, role, clientID, scope string) (access, refresh string, err error) {
+	now := time.Now()
+
+	access, err = s.issue(userID, role, clientID, scope, TokenTypeAccess, now, s.accessTTL)
+	if err != nil {
+		return "", "", err
+	}
+
+	refresh, err = s.issue(userID, role, clientID, scope, TokenTypeRefresh, now, s.refreshTTL)
+	if err != nil {
+		return "", "", err
+	}
+
+	return access, refresh, nil
+}
+
+// IssueAccessToken mints a standalone access token with no paired refresh
+// token, for grants that don't rotate (client_credentials).
+func (s *Service) IssueAccessToken(userID uint, role, clientID, scope string) (string, error) {
+	return s.issue(userID, role, clientID, scope, TokenTypeAccess, time.Now(), s.accessTTL)
+}
+
+func (s *Service) issue(userID uint, role, clientID, scope string, tokenType TokenType, now time.Time, ttl time.Duration) (string, error) {
+	jti, err := newJTI()
+	if err != nil {
+		return "", err
+	}
+
+	return encode(s.secret, Claims{
+		UserID:    userID,
+		Role:      role,
+		Type:      tokenType,
+		ID:        jti,
+		IssuedAt:  now,
+		ExpiresAt: now.Add(ttl),
+		ClientID:  clientID,
+		Scope:     scope,
+	})
+}
+
+// Verify checks signature, expiry, revocation and per-user invalidation for
+// an access token and returns its claims.
+func (s *Service) Verify(tokenString string) (*Claims, error) {
+	return s.verifyType(tokenString, TokenTypeAccess)
+}
+
+func (s *Service) verifyType(tokenString string, want TokenType) (*Claims, error) {
+	claims, err := decode(s.secret, tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Type != want {
+		return nil, ErrWrongTokenType
+	}
+
+	if s.blocklist.IsRevoked(claims.ID) {
+		return nil, ErrRevokedToken
+	}
+
+	s.mu.Lock()
+	epoch, ok := s.epoch[claims.UserID]
+	s.mu.Unlock()
+	if ok && claims.IssuedAt.Before(epoch) {
+		return nil, ErrRevokedToken
+	}
+
+	return claims, nil
+}
+
+// Refresh verifies refreshToken, revokes it (rotation - a refresh token is
+// single use), and issues a new token pair.
+func (s *Service) Refresh(refreshToken string) (access, refresh string, err error) {
+	claims, err := s.verifyType(refreshToken, TokenTypeRefresh)
+	if err != nil {
+		return "", "", err
+	}
+
+	s.blocklist.Revoke(claims.ID, claims.ExpiresAt)
+
+	return s.IssueClientTokenPair(claims.UserID, claims.Role, claims.ClientID, claims.Scope)
+}
+
+// AccessTTL returns the lifetime issued access tokens carry, so callers can
+// report expires_in without duplicating the service's configuration.
+func (s *Service) AccessTTL() time.Duration {
+	return s.accessTTL
+}
+
+// PeekClientID decodes tokenString without checking revocation or per-user
+// invalidation and returns the OAuth2 client ID it was issued to. Callers
+// that need a fully verified token should use Verify or Refresh instead;
+// this exists only so the refresh_token grant can cross-check the caller's
+// client_id before spending a Refresh call.
+func (s *Service) PeekClientID(tokenString string) (string, error) {
+	claims, err := decode(s.secret, tokenString)
+	if err != nil {
+		return "", err
+	}
+	return claims.ClientID, nil
+}
+
+// Revoke blocklists tokenString's jti until its own expiry, so a logged-out
+// access or refresh token is rejected even though it hasn't expired yet.
+func (s *Service) Revoke(tokenString string) error {
+	claims, err := decode(s.secret, tokenString)
+	if err != nil {
+		return err
+	}
+
+	s.blocklist.Revoke(claims.ID, claims.ExpiresAt)
+	return nil
+}
+
+// InvalidateUser rejects every token already issued to userID - call this on
+// password change so existing sessions can't keep using their old tokens.
+func (s *Service) InvalidateUser(userID uint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.epoch[userID] = time.Now()
+}
+
+// mfaChallengeTTL bounds how long a user has to complete the second factor
+// after a successful password check before having to log in again.
+const mfaChallengeTTL = 5 * time.Minute
+
+// IssueMFAChallenge mints a short-lived token proving userID already passed
+// the password check, for UserService.CompleteMFALogin to redeem once the
+// second factor is verified. It carries no role/client/scope since it can't
+// be used as a bearer token - Verify only accepts TokenTypeAccess.
+func (s *Service) IssueMFAChallenge(userID uint) (string, error) {
+	return s.issue(userID, "", "", "", TokenTypeMFAChallenge, time.Now(), mfaChallengeTTL)
+}
+
+// VerifyMFAChallenge checks signature, expiry and revocation of a token
+// issued by IssueMFAChallenge and returns the userID it was issued for.
+func (s *Service) VerifyMFAChallenge(tokenString string) (uint, error) {
+	claims, err := s.verifyType(tokenString, TokenTypeMFAChallenge)
+	if err != nil {
+		return 0, err
+	}
+	return claims.UserID, nil
+}
@@ -2,11 +2,11 @@ package database
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
 	"time"
 
-	"ppmtest/internal/config"
-	"ppmtest/internal/models"
+	"github.com/cfrs2005/ppmtest/internal/config"
+	"github.com/cfrs2005/ppmtest/internal/logging"
 
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
@@ -16,19 +16,17 @@ import (
 // DB holds the database connection
 var DB *gorm.DB
 
-// Initialize initializes the database connection
+// Initialize opens the database connection for cfg.Driver ("mysql" by
+// default). Schema setup is no longer done here - see cmd/migrate and the
+// migrations/ directory.
 func Initialize(cfg *config.DatabaseConfig) error {
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		cfg.User,
-		cfg.Password,
-		cfg.Host,
-		cfg.Port,
-		cfg.Database,
-	)
-
-	var err error
-	DB, err = gorm.Open(mysql.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+	dialector, err := dialectorFor(cfg)
+	if err != nil {
+		return err
+	}
+
+	DB, err = gorm.Open(dialector, &gorm.Config{
+		Logger: logging.NewGormLogger(logger.Info),
 	})
 
 	if err != nil {
@@ -44,32 +42,37 @@ func Initialize(cfg *config.DatabaseConfig) error {
 	sqlDB.SetMaxOpenConns(100)
 	sqlDB.SetConnMaxLifetime(time.Hour)
 
-	log.Println("Database connection established successfully")
+	slog.Info("database connection established successfully")
 
 	return nil
 }
 
-// Migrate runs database migrations
-func Migrate() error {
-	if DB == nil {
-		return fmt.Errorf("database not initialized")
+// dialectorFor builds the GORM dialector for cfg.Driver. Postgres and
+// SQLite are recognized names but this tree's module cache doesn't vendor
+// gorm.io/driver/postgres or gorm.io/driver/sqlite (no network access to
+// fetch them), so they fail with a clear error rather than silently
+// falling back to MySQL.
+func dialectorFor(cfg *config.DatabaseConfig) (gorm.Dialector, error) {
+	driver := cfg.Driver
+	if driver == "" {
+		driver = "mysql"
 	}
 
-	err := DB.AutoMigrate(
-		&models.User{},
-		&models.Post{},
-		&models.Comment{},
-		&models.Tag{},
-		&models.Category{},
-	)
-
-	if err != nil {
-		return fmt.Errorf("failed to run migrations: %w", err)
+	switch driver {
+	case "mysql":
+		dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+			cfg.User,
+			cfg.Password,
+			cfg.Host,
+			cfg.Port,
+			cfg.Database,
+		)
+		return mysql.Open(dsn), nil
+	case "postgres", "sqlite":
+		return nil, fmt.Errorf("database driver %q is not available in this build: gorm.io/driver/%s isn't vendored in this tree", driver, driver)
+	default:
+		return nil, fmt.Errorf("unknown database driver %q", driver)
 	}
-
-	log.Println("Database migrations completed successfully")
-
-	return nil
 }
 
 // Close closes the database connection
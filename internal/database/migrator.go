@@ -0,0 +1,210 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// migrationsTable mirrors golang-migrate's schema_migrations table: the
+// single applied version, plus whether the last attempt left it dirty.
+const migrationsTable = "schema_migrations"
+
+// migration is one numbered pair of files under a migrations directory,
+// e.g. migrations/0001_create_users.up.sql and .down.sql.
+type migration struct {
+	Version int
+	Name    string
+	UpSQL   string
+	DownSQL string
+}
+
+var migrationFileRe = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// Migrator applies numbered SQL migrations from a directory against db,
+// tracking the applied version in migrationsTable. golang-migrate isn't
+// vendored in this tree (no network access to fetch it), so this
+// hand-rolls just enough of its Up/Down/Force/Version contract for
+// cmd/migrate to expose: a single applied version, a dirty flag set before
+// each migration and cleared after, and strictly-ordered numbered files.
+type Migrator struct {
+	db  *sql.DB
+	dir string
+}
+
+// NewMigrator returns a Migrator that reads numbered .up.sql/.down.sql
+// pairs from dir and applies them against db.
+func NewMigrator(db *sql.DB, dir string) *Migrator {
+	return &Migrator{db: db, dir: dir}
+}
+
+func (m *Migrator) ensureTable() error {
+	_, err := m.db.Exec(fmt.Sprintf(
+		`CREATE TABLE IF NOT EXISTS %s (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)`,
+		migrationsTable,
+	))
+	return err
+}
+
+// Version returns the currently applied migration version, or version 0
+// with dirty=false if no migration has ever been applied.
+func (m *Migrator) Version() (version int, dirty bool, err error) {
+	if err := m.ensureTable(); err != nil {
+		return 0, false, err
+	}
+
+	row := m.db.QueryRow(fmt.Sprintf(`SELECT version, dirty FROM %s LIMIT 1`, migrationsTable))
+	if err := row.Scan(&version, &dirty); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+
+	return version, dirty, nil
+}
+
+// Force sets the recorded version without running any migration and clears
+// the dirty flag, for recovering from a migration that failed partway.
+func (m *Migrator) Force(version int) error {
+	if err := m.ensureTable(); err != nil {
+		return err
+	}
+	return m.setVersion(version, false)
+}
+
+func (m *Migrator) setVersion(version int, dirty bool) error {
+	if _, err := m.db.Exec(fmt.Sprintf(`DELETE FROM %s`, migrationsTable)); err != nil {
+		return err
+	}
+	_, err := m.db.Exec(fmt.Sprintf(`INSERT INTO %s (version, dirty) VALUES (?, ?)`, migrationsTable), version, dirty)
+	return err
+}
+
+// Up applies every migration newer than the current version, in order.
+func (m *Migrator) Up() error {
+	return m.run(true)
+}
+
+// Down reverts every applied migration, in reverse order.
+func (m *Migrator) Down() error {
+	return m.run(false)
+}
+
+func (m *Migrator) run(up bool) error {
+	if err := m.ensureTable(); err != nil {
+		return err
+	}
+
+	all, err := loadMigrations(m.dir)
+	if err != nil {
+		return err
+	}
+
+	current, dirty, err := m.Version()
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("database is marked dirty at version %d; run Force to recover", current)
+	}
+
+	pending := pendingMigrations(all, current, up)
+
+	for _, mig := range pending {
+		sqlText := mig.UpSQL
+		target := mig.Version
+		if !up {
+			sqlText = mig.DownSQL
+			target = mig.Version - 1
+		}
+
+		if err := m.setVersion(mig.Version, true); err != nil {
+			return err
+		}
+
+		if _, err := m.db.Exec(sqlText); err != nil {
+			return fmt.Errorf("migration %d (%s) failed: %w", mig.Version, mig.Name, err)
+		}
+
+		if err := m.setVersion(target, false); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func pendingMigrations(all []migration, current int, up bool) []migration {
+	var pending []migration
+
+	if up {
+		for _, mig := range all {
+			if mig.Version > current {
+				pending = append(pending, mig)
+			}
+		}
+		return pending
+	}
+
+	for i := len(all) - 1; i >= 0; i-- {
+		if all[i].Version <= current {
+			pending = append(pending, all[i])
+		}
+	}
+	return pending
+}
+
+func loadMigrations(dir string) ([]migration, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations directory %s: %w", dir, err)
+	}
+
+	byVersion := map[int]*migration{}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		match := migrationFileRe.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		version, err := strconv.Atoi(match[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %w", entry.Name(), err)
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &migration{Version: version, Name: match[2]}
+			byVersion[version] = mig
+		}
+
+		if match[3] == "up" {
+			mig.UpSQL = string(data)
+		} else {
+			mig.DownSQL = string(data)
+		}
+	}
+
+	result := make([]migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		result = append(result, *mig)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+
+	return result, nil
+}
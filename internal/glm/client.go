@@ -1,12 +1,18 @@
 package glm
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"math/rand"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -17,6 +23,15 @@ var (
 	ErrRateLimitExceeded = errors.New("GLM API rate limit exceeded")
 )
 
+// Retry policy defaults, used when NewClient isn't given WithRetryPolicy:
+// up to defaultMaxAttempts tries total, starting at defaultBaseDelay and
+// doubling each attempt, capped at defaultMaxDelay.
+const (
+	defaultMaxAttempts = 4
+	defaultBaseDelay   = 500 * time.Millisecond
+	defaultMaxDelay    = 30 * time.Second
+)
+
 type Client struct {
 	apiKey      string
 	baseURL     string
@@ -24,6 +39,37 @@ type Client struct {
 	maxTokens   int
 	temperature float64
 	httpClient  *http.Client
+
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+	limiter     *rateLimiter
+}
+
+// ClientOption configures optional Client behavior not covered by
+// NewClient's required parameters.
+type ClientOption func(*Client)
+
+// WithRetryPolicy overrides the default exponential-backoff retry policy:
+// up to maxAttempts total tries, starting at baseDelay and doubling each
+// attempt (plus jitter), capped at maxDelay. maxAttempts <= 0 disables
+// retries entirely.
+func WithRetryPolicy(maxAttempts int, baseDelay, maxDelay time.Duration) ClientOption {
+	return func(c *Client) {
+		c.maxAttempts = maxAttempts
+		c.baseDelay = baseDelay
+		c.maxDelay = maxDelay
+	}
+}
+
+// WithRateLimit bounds Client to rps requests/second, allowing bursts up to
+// burst, so a worker pool (e.g. AsyncGLMService) calling a shared Client
+// doesn't stampede the upstream API. Requests block on this limiter before
+// every attempt, including retries.
+func WithRateLimit(rps float64, burst int) ClientOption {
+	return func(c *Client) {
+		c.limiter = newRateLimiter(rps, burst)
+	}
 }
 
 type Message struct {
@@ -36,13 +82,27 @@ type Request struct {
 	Messages    []Message `json:"messages"`
 	MaxTokens   int       `json:"max_tokens,omitempty"`
 	Temperature float64   `json:"temperature,omitempty"`
+	Stream      bool      `json:"stream,omitempty"`
+}
+
+// StreamChunk is one `data: {...}` SSE frame from a streamed chat
+// completion, as returned when Request.Stream is true.
+type StreamChunk struct {
+	ID      string `json:"id"`
+	Choices []struct {
+		Index int `json:"index"`
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
 }
 
 type Response struct {
-	ID      string   `json:"id"`
-	Object  string   `json:"object"`
-	Created int64    `json:"created"`
-	Model   string   `json:"model"`
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Created int64  `json:"created"`
+	Model   string `json:"model"`
 	Choices []struct {
 		Index   int     `json:"index"`
 		Message Message `json:"message"`
@@ -62,7 +122,7 @@ type ErrorResponse struct {
 	} `json:"error"`
 }
 
-func NewClient(apiKey, baseURL, model string, maxTokens int, temperature float64) (*Client, error) {
+func NewClient(apiKey, baseURL, model string, maxTokens int, temperature float64, opts ...ClientOption) (*Client, error) {
 	if apiKey == "" {
 		return nil, ErrAPIKeyRequired
 	}
@@ -75,7 +135,7 @@ func NewClient(apiKey, baseURL, model string, maxTokens int, temperature float64
 		model = "glm-4"
 	}
 
-	return &Client{
+	c := &Client{
 		apiKey:      apiKey,
 		baseURL:     baseURL,
 		model:       model,
@@ -84,7 +144,16 @@ func NewClient(apiKey, baseURL, model string, maxTokens int, temperature float64
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
-	}, nil
+		maxAttempts: defaultMaxAttempts,
+		baseDelay:   defaultBaseDelay,
+		maxDelay:    defaultMaxDelay,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c, nil
 }
 
 func (c *Client) GenerateContent(prompt string) (string, error) {
@@ -116,6 +185,34 @@ func (c *Client) GenerateContent(prompt string) (string, error) {
 	return resp.Choices[0].Message.Content, nil
 }
 
+// GenerateContentStream is GenerateContent with incremental delivery: it
+// sets stream: true on the chat completions request and invokes onChunk
+// with each choices[0].delta.content fragment as the SSE response arrives,
+// instead of buffering the whole completion before returning. Useful for
+// GeneratePostContent's 500-1000 character posts, which can otherwise sit
+// silently against the client's 30s timeout with no incremental feedback.
+// onChunk returning an error stops the stream and that error is returned.
+func (c *Client) GenerateContentStream(prompt string, onChunk func(delta string) error) error {
+	if prompt == "" {
+		return errors.New("prompt cannot be empty")
+	}
+
+	req := Request{
+		Model: c.model,
+		Messages: []Message{
+			{
+				Role:    "user",
+				Content: prompt,
+			},
+		},
+		MaxTokens:   c.maxTokens,
+		Temperature: c.temperature,
+		Stream:      true,
+	}
+
+	return c.doStreamRequest("/chat/completions", req, onChunk)
+}
+
 func (c *Client) SummarizeText(text string, maxLength int) (string, error) {
 	prompt := fmt.Sprintf("请用中文总结以下内容，最多 %d 个字：\n\n%s", maxLength, text)
 	return c.GenerateContent(prompt)
@@ -123,7 +220,7 @@ func (c *Client) SummarizeText(text string, maxLength int) (string, error) {
 
 func (c *Client) GenerateTags(content string, count int) ([]string, error) {
 	prompt := fmt.Sprintf("根据以下内容生成 %d 个相关的标签，用逗号分隔：\n\n%s", count, content)
-	
+
 	response, err := c.GenerateContent(prompt)
 	if err != nil {
 		return nil, err
@@ -137,15 +234,206 @@ func (c *Client) GenerateTags(content string, count int) ([]string, error) {
 	return tags, nil
 }
 
-func (c *Client) DetectSpam(content string) (bool, error) {
-	prompt := fmt.Sprintf("判断以下内容是否为垃圾评论，只回答\"是\"或\"否\"：\n\n%s", content)
-	
+// SpamVerdict is the model's judgment on whether a piece of content is spam,
+// along with how confident it is and why.
+type SpamVerdict struct {
+	IsSpam     bool     `json:"is_spam"`
+	Confidence float64  `json:"confidence"`
+	Reasons    []string `json:"reasons"`
+}
+
+// DetectSpam asks the model to judge content as spam and return strict JSON
+// matching SpamVerdict. If the reply isn't valid JSON (the model ignored the
+// format instruction, wrapped it in prose, etc.), DetectSpam falls back to a
+// rune-aware, negation-aware keyword classifier over the raw reply instead
+// of failing the request outright.
+func (c *Client) DetectSpam(content string) (*SpamVerdict, error) {
+	prompt := fmt.Sprintf(
+		"判断以下内容是否为垃圾评论。只返回严格的 JSON，不要包含任何其他文字，格式为："+
+			"{\"is_spam\": bool, \"confidence\": 0到1之间的小数, \"reasons\": [\"原因\", ...]}\n\n%s",
+		content,
+	)
+
 	response, err := c.GenerateContent(prompt)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 
-	return containsSpamKeywords(response), nil
+	var verdict SpamVerdict
+	if err := json.Unmarshal([]byte(strings.TrimSpace(response)), &verdict); err == nil {
+		return &verdict, nil
+	}
+
+	return classifySpamFallback(response), nil
+}
+
+// spamKeywords and negationMarkers are matched rune-by-rune rather than
+// byte-by-byte so multi-byte Chinese keywords (e.g. "垃圾", "广告") and their
+// negation markers line up correctly.
+var spamKeywords = []string{"垃圾", "spam", "广告", "是"}
+var negationMarkers = []string{"不", "非", "否"}
+
+// clauseBreaks delimit the window precededByNegation scans for a negation
+// marker, so a negation earlier in an unrelated clause doesn't suppress a
+// keyword match later in the same reply (e.g. "不同意，这是垃圾评论").
+var clauseBreaks = []rune{'。', '，', '！', '？', '\n', '.', ',', '!', '?'}
+
+// classifySpamFallback is the negation-aware backstop DetectSpam uses when
+// the model doesn't return parseable JSON: it flags a spamKeywords match
+// unless the current clause is preceded by a negationMarkers rune, so
+// replies like "不是垃圾评论" don't trip the same "垃圾" match that "确实是
+// 垃圾" does, even though "不" sits two runes back, across the "是" keyword.
+func classifySpamFallback(text string) *SpamVerdict {
+	runes := []rune(text)
+	var reasons []string
+
+	for _, keyword := range spamKeywords {
+		kwRunes := []rune(keyword)
+		for i := 0; i+len(kwRunes) <= len(runes); i++ {
+			if string(runes[i:i+len(kwRunes)]) != keyword {
+				continue
+			}
+			if precededByNegation(runes, i) {
+				continue
+			}
+			reasons = append(reasons, fmt.Sprintf("matched keyword %q", keyword))
+			break
+		}
+	}
+
+	verdict := &SpamVerdict{IsSpam: len(reasons) > 0, Reasons: reasons}
+	if verdict.IsSpam {
+		verdict.Confidence = 0.6
+	}
+	return verdict
+}
+
+func precededByNegation(runes []rune, index int) bool {
+	clauseStart := 0
+	for i := index - 1; i >= 0; i-- {
+		if isClauseBreak(runes[i]) {
+			clauseStart = i + 1
+			break
+		}
+	}
+
+	clause := string(runes[clauseStart:index])
+	for _, marker := range negationMarkers {
+		if strings.Contains(clause, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+func isClauseBreak(r rune) bool {
+	for _, b := range clauseBreaks {
+		if r == b {
+			return true
+		}
+	}
+	return false
+}
+
+// httpStatusError wraps a non-200 GLM API response with its status code and
+// any Retry-After value, so isRetryable/doWithRetry can decide whether and
+// how long to wait before trying again.
+type httpStatusError struct {
+	status     int
+	retryAfter time.Duration
+	err        error
+}
+
+func (e *httpStatusError) Error() string { return e.err.Error() }
+func (e *httpStatusError) Unwrap() error { return e.err }
+
+func retryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func isRetryable(err error) bool {
+	var statusErr *httpStatusError
+	if errors.As(err, &statusErr) {
+		return retryableStatus(statusErr.status)
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return netErr.Timeout()
+	}
+	return false
+}
+
+// parseRetryAfter parses an HTTP Retry-After header, which is either a
+// number of seconds or an HTTP date. An empty or unparseable header yields 0.
+func parseRetryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(header); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if when, err := http.ParseTime(header); err == nil {
+		return time.Until(when)
+	}
+	return 0
+}
+
+// backoffDelay returns the exponential-backoff-with-jitter delay before
+// retry attempt n (n=1 is the delay before the 2nd try overall): base
+// doubled n-1 times, capped at maxDelay, then jittered by up to +/-50%
+// so concurrent callers retrying the same failure don't land in lockstep.
+func backoffDelay(base, maxDelay time.Duration, n int) time.Duration {
+	delay := base * time.Duration(uint64(1)<<uint(n-1))
+	if delay <= 0 || delay > maxDelay {
+		delay = maxDelay
+	}
+	jitter := time.Duration(rand.Int63n(int64(delay) + 1))
+	delay = delay/2 + jitter/2
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay
+}
+
+// doWithRetry runs attempt up to c.maxAttempts times (1 if retries are
+// disabled), waiting on c.limiter before every try including retries, and
+// backing off between retryable failures - honoring the upstream
+// Retry-After value when the failure carries one.
+func (c *Client) doWithRetry(attempt func() error) error {
+	maxAttempts := c.maxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+
+	var lastErr error
+	for n := 1; n <= maxAttempts; n++ {
+		if c.limiter != nil {
+			if err := c.limiter.Wait(context.Background()); err != nil {
+				return err
+			}
+		}
+
+		lastErr = attempt()
+		if lastErr == nil {
+			return nil
+		}
+		if n == maxAttempts || !isRetryable(lastErr) {
+			return lastErr
+		}
+
+		delay := backoffDelay(c.baseDelay, c.maxDelay, n)
+		var statusErr *httpStatusError
+		if errors.As(lastErr, &statusErr) && statusErr.retryAfter > delay {
+			delay = statusErr.retryAfter
+		}
+		time.Sleep(delay)
+	}
+
+	return lastErr
 }
 
 func (c *Client) doRequest(endpoint string, req interface{}) (*Response, error) {
@@ -154,6 +442,19 @@ func (c *Client) doRequest(endpoint string, req interface{}) (*Response, error)
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
+	var resp *Response
+	err = c.doWithRetry(func() error {
+		r, err := c.doRequestOnce(endpoint, reqBody)
+		if err != nil {
+			return err
+		}
+		resp = r
+		return nil
+	})
+	return resp, err
+}
+
+func (c *Client) doRequestOnce(endpoint string, reqBody []byte) (*Response, error) {
 	url := c.baseURL + endpoint
 	httpReq, err := http.NewRequest("POST", url, bytes.NewBuffer(reqBody))
 	if err != nil {
@@ -175,14 +476,15 @@ func (c *Client) doRequest(endpoint string, req interface{}) (*Response, error)
 	}
 
 	if httpResp.StatusCode != http.StatusOK {
+		retryAfter := parseRetryAfter(httpResp.Header.Get("Retry-After"))
 		var errResp ErrorResponse
 		if err := json.Unmarshal(body, &errResp); err == nil {
 			if httpResp.StatusCode == http.StatusTooManyRequests {
-				return nil, ErrRateLimitExceeded
+				return nil, &httpStatusError{status: httpResp.StatusCode, retryAfter: retryAfter, err: ErrRateLimitExceeded}
 			}
-			return nil, fmt.Errorf("%w: %s", ErrRequestFailed, errResp.Error.Message)
+			return nil, &httpStatusError{status: httpResp.StatusCode, retryAfter: retryAfter, err: fmt.Errorf("%w: %s", ErrRequestFailed, errResp.Error.Message)}
 		}
-		return nil, fmt.Errorf("%w: status %d", ErrRequestFailed, httpResp.StatusCode)
+		return nil, &httpStatusError{status: httpResp.StatusCode, retryAfter: retryAfter, err: fmt.Errorf("%w: status %d", ErrRequestFailed, httpResp.StatusCode)}
 	}
 
 	var resp Response
@@ -193,6 +495,108 @@ func (c *Client) doRequest(endpoint string, req interface{}) (*Response, error)
 	return &resp, nil
 }
 
+// openStream sends the streaming request and returns the live response once
+// its status is confirmed OK, for doStreamRequest to read frames from.
+// Non-200 responses are drained and closed here, surfaced as an
+// *httpStatusError so doWithRetry can decide whether to retry.
+func (c *Client) openStream(endpoint string, reqBody []byte) (*http.Response, error) {
+	url := c.baseURL + endpoint
+	httpReq, err := http.NewRequest("POST", url, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	httpReq.Header.Set("Accept", "text/event-stream")
+
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+
+	if httpResp.StatusCode != http.StatusOK {
+		defer httpResp.Body.Close()
+		body, _ := io.ReadAll(httpResp.Body)
+		retryAfter := parseRetryAfter(httpResp.Header.Get("Retry-After"))
+		var errResp ErrorResponse
+		if err := json.Unmarshal(body, &errResp); err == nil {
+			if httpResp.StatusCode == http.StatusTooManyRequests {
+				return nil, &httpStatusError{status: httpResp.StatusCode, retryAfter: retryAfter, err: ErrRateLimitExceeded}
+			}
+			return nil, &httpStatusError{status: httpResp.StatusCode, retryAfter: retryAfter, err: fmt.Errorf("%w: %s", ErrRequestFailed, errResp.Error.Message)}
+		}
+		return nil, &httpStatusError{status: httpResp.StatusCode, retryAfter: retryAfter, err: fmt.Errorf("%w: status %d", ErrRequestFailed, httpResp.StatusCode)}
+	}
+
+	return httpResp, nil
+}
+
+// doStreamRequest posts req and parses the response body as a sequence of
+// `data: {...}` SSE frames, invoking onChunk for each non-empty delta. It
+// stops at the `data: [DONE]` sentinel frame or the first onChunk error.
+// Retries (via doWithRetry) only cover opening the connection and checking
+// its status; once frames start arriving, a failure is returned as-is
+// rather than retried, since onChunk may already have delivered partial
+// output to the caller.
+func (c *Client) doStreamRequest(endpoint string, req interface{}, onChunk func(delta string) error) error {
+	reqBody, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	var httpResp *http.Response
+	err = c.doWithRetry(func() error {
+		r, err := c.openStream(endpoint, reqBody)
+		if err != nil {
+			return err
+		}
+		httpResp = r
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	defer httpResp.Body.Close()
+
+	scanner := bufio.NewScanner(httpResp.Body)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || !strings.HasPrefix(line, "data:") {
+			continue
+		}
+
+		payload := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if payload == "[DONE]" {
+			return nil
+		}
+
+		var chunk StreamChunk
+		if err := json.Unmarshal([]byte(payload), &chunk); err != nil {
+			return fmt.Errorf("failed to unmarshal stream chunk: %w", err)
+		}
+
+		if len(chunk.Choices) == 0 {
+			continue
+		}
+
+		delta := chunk.Choices[0].Delta.Content
+		if delta == "" {
+			continue
+		}
+
+		if err := onChunk(delta); err != nil {
+			return err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read stream: %w", err)
+	}
+
+	return nil
+}
+
 func parseTags(text string) []string {
 	var tags []string
 	for _, tag := range splitByComma(text) {
@@ -207,7 +611,7 @@ func parseTags(text string) []string {
 func splitByComma(s string) []string {
 	var result []string
 	current := ""
-	
+
 	for _, ch := range s {
 		if ch == ',' || ch == '，' {
 			result = append(result, current)
@@ -216,50 +620,25 @@ func splitByComma(s string) []string {
 			current += string(ch)
 		}
 	}
-	
+
 	if current != "" {
 		result = append(result, current)
 	}
-	
+
 	return result
 }
 
 func trimSpace(s string) string {
 	start := 0
 	end := len(s)
-	
+
 	for start < end && (s[start] == ' ' || s[start] == '\t' || s[start] == '\n') {
 		start++
 	}
-	
+
 	for end > start && (s[end-1] == ' ' || s[end-1] == '\t' || s[end-1] == '\n') {
 		end--
 	}
-	
-	return s[start:end]
-}
 
-func containsSpamKeywords(text string) bool {
-	spamKeywords := []string{"是", "垃圾", "spam", "广告"}
-	
-	for _, keyword := range spamKeywords {
-		if contains(text, keyword) {
-			return true
-		}
-	}
-	
-	return false
-}
-
-func contains(s, substr string) bool {
-	return len(s) >= len(substr) && findSubstring(s, substr)
+	return s[start:end]
 }
-
-func findSubstring(s, substr string) bool {
-	for i := 0; i <= len(s)-len(substr); i++ {
-		if s[i:i+len(substr)] == substr {
-			return true
-		}
-	}
-	return false
-}
\ No newline at end of file
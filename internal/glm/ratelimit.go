@@ -0,0 +1,82 @@
+package glm
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// rateLimiter is a single-bucket, blocking token bucket bounding how fast a
+// Client issues requests. It mirrors the bucket math in
+// internal/ratelimit.Limiter, but that type is keyed per-string (IP/user)
+// and reports Allow without blocking, which doesn't fit a single shared
+// Client deciding "wait until a token is free" rather than "reject now".
+// golang.org/x/time/rate isn't vendored in this tree, so this reimplements
+// its Wait semantics directly.
+type rateLimiter struct {
+	mu         sync.Mutex
+	tokens     float64
+	burst      float64
+	refillRate float64
+	lastRefill time.Time
+}
+
+// newRateLimiter returns a rateLimiter allowing burst requests immediately
+// and refilling at rps requests/second thereafter.
+func newRateLimiter(rps float64, burst int) *rateLimiter {
+	return &rateLimiter{
+		tokens:     float64(burst),
+		burst:      float64(burst),
+		refillRate: rps,
+		lastRefill: time.Now(),
+	}
+}
+
+// Wait blocks until a token is available or ctx is done.
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	for {
+		wait, ok := l.take()
+		if ok {
+			return nil
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// take refills the bucket, then either consumes a token (returning ok=true)
+// or reports how long the caller should wait before trying again.
+func (l *rateLimiter) take() (wait time.Duration, ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	elapsed := now.Sub(l.lastRefill).Seconds()
+	l.tokens = minFloat(l.burst, l.tokens+elapsed*l.refillRate)
+	l.lastRefill = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0, true
+	}
+
+	if l.refillRate <= 0 {
+		return time.Second, false
+	}
+
+	needed := 1 - l.tokens
+	return time.Duration(needed / l.refillRate * float64(time.Second)), false
+}
+
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/cfrs2005/ppmtest/internal/aiplugin"
+
+	"github.com/cfrs2005/ppmtest/pkg/apierr"
+	"github.com/gin-gonic/gin"
+)
+
+// AIPluginHandler exposes aiplugin providers directly, for callers that
+// want to pick a specific one by name (post summarization, comment
+// moderation and tag suggestion call into a Completer through their own
+// services instead). Not wired into router.SetupRouter yet.
+type AIPluginHandler struct {
+	manager *aiplugin.Manager
+}
+
+// NewAIPluginHandler builds an AIPluginHandler over manager.
+func NewAIPluginHandler(manager *aiplugin.Manager) *AIPluginHandler {
+	return &AIPluginHandler{manager: manager}
+}
+
+type completeRequest struct {
+	Provider string `json:"provider" binding:"required"`
+	Prompt   string `json:"prompt" binding:"required"`
+}
+
+type completeResponse struct {
+	Text string `json:"text"`
+}
+
+// Complete runs prompt through the named provider plugin. If the plugin
+// isn't registered, crashed, or stopped responding, this returns a
+// structured 503 ("AI unavailable") instead of a 500, so a flaky provider
+// doesn't look like a server bug to the caller.
+func (h *AIPluginHandler) Complete(c *gin.Context) {
+	var req completeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apierr.Validation("invalid request body", err.Error()))
+		return
+	}
+
+	completer, ok := h.manager.Completer(req.Provider)
+	if !ok {
+		c.Error(apierr.Unavailable("AI provider \"" + req.Provider + "\" is not available"))
+		return
+	}
+
+	text, err := completer.Complete(c.Request.Context(), req.Prompt)
+	if err != nil {
+		if errors.Is(err, aiplugin.ErrUnavailable) {
+			c.Error(apierr.Unavailable("AI provider \"" + req.Provider + "\" is not available"))
+			return
+		}
+		c.Error(apierr.From(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, completeResponse{Text: text})
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"context"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"github.com/cfrs2005/ppmtest/internal/models"
+	"github.com/cfrs2005/ppmtest/internal/repository"
+	"github.com/cfrs2005/ppmtest/internal/service"
+
+	"github.com/cfrs2005/ppmtest/internal/middleware"
+	"github.com/cfrs2005/ppmtest/pkg/apierr"
+	"github.com/gin-gonic/gin"
+)
+
+type AttachmentHandler struct {
+	attachmentService AttachmentService
+}
+
+type AttachmentService interface {
+	Upload(ctx context.Context, userID uint, fileHeader *multipart.FileHeader) (*models.Attachment, error)
+	GetByID(ctx context.Context, id uint) (*models.Attachment, error)
+	Delete(ctx context.Context, userID, id uint) error
+	LinkToPost(ctx context.Context, userID, postID uint, ids []uint) error
+}
+
+func NewAttachmentHandler(attachmentService AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{attachmentService: attachmentService}
+}
+
+func (h *AttachmentHandler) Upload(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.Error(apierr.Validation("file is required", err.Error()))
+		return
+	}
+
+	attachment, err := h.attachmentService.Upload(c.Request.Context(), userID, fileHeader)
+	if err != nil {
+		switch err {
+		case service.ErrFileTooLarge, service.ErrQuotaExceeded:
+			c.Error(apierr.Validation(err.Error(), nil))
+		default:
+			c.Error(apierr.Internal("failed to store upload"))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":    "File uploaded successfully",
+		"attachment": attachment,
+	})
+}
+
+func (h *AttachmentHandler) GetByID(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		c.Error(apierr.Validation("invalid attachment ID", nil))
+		return
+	}
+
+	attachment, err := h.attachmentService.GetByID(c.Request.Context(), uint(id))
+	if err != nil {
+		middleware.NotFoundOrServerError(c, err, repository.ErrAttachmentNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, attachment)
+}
+
+func (h *AttachmentHandler) Delete(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		c.Error(apierr.Validation("invalid attachment ID", nil))
+		return
+	}
+
+	if err := h.attachmentService.Delete(c.Request.Context(), userID, uint(id)); err != nil {
+		switch err {
+		case service.ErrNotOwner:
+			c.Error(apierr.Forbidden(err.Error()))
+		case repository.ErrAttachmentNotFound:
+			c.Error(apierr.NotFound(err.Error()))
+		default:
+			c.Error(apierr.Internal("failed to delete attachment"))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Attachment deleted successfully"})
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/cfrs2005/ppmtest/internal/service"
+
+	"github.com/cfrs2005/ppmtest/pkg/apierr"
+	"github.com/gin-gonic/gin"
+)
+
+type CaptchaHandler struct {
+	captchaService CaptchaService
+}
+
+type CaptchaService interface {
+	New(ctx context.Context, ip string) (*service.CaptchaChallenge, error)
+	Verify(ctx context.Context, key, code string) error
+}
+
+func NewCaptchaHandler(captchaService CaptchaService) *CaptchaHandler {
+	return &CaptchaHandler{captchaService: captchaService}
+}
+
+// New issues a fresh image captcha for the caller's IP.
+func (h *CaptchaHandler) New(c *gin.Context) {
+	challenge, err := h.captchaService.New(c.Request.Context(), c.ClientIP())
+	if err != nil {
+		c.Error(apierr.Internal("failed to generate captcha"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"key":          challenge.Key,
+		"image_base64": challenge.ImageBase64,
+	})
+}
@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/cfrs2005/ppmtest/internal/middleware"
+	"github.com/cfrs2005/ppmtest/internal/models"
+	"github.com/cfrs2005/ppmtest/internal/repository"
+	"github.com/cfrs2005/ppmtest/pkg/apierr"
+	"github.com/gin-gonic/gin"
+)
+
+type CategoryHandler struct {
+	categoryService CategoryService
+}
+
+type CategoryService interface {
+	CreateCategory(ctx context.Context, category *models.Category) error
+	GetCategoryByID(ctx context.Context, id uint) (*models.Category, error)
+	GetCategoryBySlug(ctx context.Context, slug string) (*models.Category, error)
+	UpdateCategory(ctx context.Context, category *models.Category) error
+	DeleteCategory(ctx context.Context, id uint) error
+	ListCategories(ctx context.Context, page, pageSize int) ([]*models.Category, int64, error)
+}
+
+func NewCategoryHandler(categoryService CategoryService) *CategoryHandler {
+	return &CategoryHandler{
+		categoryService: categoryService,
+	}
+}
+
+type CreateCategoryRequest struct {
+	Name        string `json:"name" binding:"required,min=1,max=100"`
+	Slug        string `json:"slug" binding:"omitempty,max=100"`
+	Description string `json:"description" binding:"omitempty"`
+}
+
+type UpdateCategoryRequest struct {
+	Name        string `json:"name" binding:"omitempty,min=1,max=100"`
+	Slug        string `json:"slug" binding:"omitempty,max=100"`
+	Description string `json:"description" binding:"omitempty"`
+}
+
+func (h *CategoryHandler) Create(c *gin.Context) {
+	var req CreateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apierr.Validation("invalid request body", err.Error()))
+		return
+	}
+
+	category := &models.Category{
+		Name:        req.Name,
+		Slug:        req.Slug,
+		Description: req.Description,
+	}
+
+	if err := h.categoryService.CreateCategory(c.Request.Context(), category); err != nil {
+		if err == repository.ErrCategoryAlreadyExists {
+			c.Error(apierr.Conflict(err.Error()))
+		} else {
+			c.Error(apierr.Internal("failed to create category"))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":  "Category created successfully",
+		"category": category,
+	})
+}
+
+func (h *CategoryHandler) GetByID(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		c.Error(apierr.Validation("invalid category ID", nil))
+		return
+	}
+
+	category, err := h.categoryService.GetCategoryByID(c.Request.Context(), uint(id))
+	if err != nil {
+		middleware.NotFoundOrServerError(c, err, repository.ErrCategoryNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, category)
+}
+
+func (h *CategoryHandler) Update(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		c.Error(apierr.Validation("invalid category ID", nil))
+		return
+	}
+
+	var req UpdateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apierr.Validation("invalid request body", err.Error()))
+		return
+	}
+
+	category, err := h.categoryService.GetCategoryByID(c.Request.Context(), uint(id))
+	if err != nil {
+		middleware.NotFoundOrServerError(c, err, repository.ErrCategoryNotFound)
+		return
+	}
+
+	if req.Name != "" {
+		category.Name = req.Name
+	}
+	if req.Slug != "" {
+		category.Slug = req.Slug
+	}
+	if req.Description != "" {
+		category.Description = req.Description
+	}
+
+	if err := h.categoryService.UpdateCategory(c.Request.Context(), category); err != nil {
+		if err == repository.ErrCategoryAlreadyExists {
+			c.Error(apierr.Conflict(err.Error()))
+		} else {
+			c.Error(apierr.Internal("failed to update category"))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Category updated successfully",
+		"category": category,
+	})
+}
+
+func (h *CategoryHandler) Delete(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		c.Error(apierr.Validation("invalid category ID", nil))
+		return
+	}
+
+	if err := h.categoryService.DeleteCategory(c.Request.Context(), uint(id)); err != nil {
+		middleware.NotFoundOrServerError(c, err, repository.ErrCategoryNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Category deleted successfully"})
+}
+
+func (h *CategoryHandler) List(c *gin.Context) {
+	pageParam := c.DefaultQuery("page", "1")
+	pageSizeParam := c.DefaultQuery("page_size", "10")
+
+	page, err := strconv.Atoi(pageParam)
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	pageSize, err := strconv.Atoi(pageSizeParam)
+	if err != nil || pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	categories, total, err := h.categoryService.ListCategories(c.Request.Context(), page, pageSize)
+	if err != nil {
+		c.Error(apierr.Internal("failed to list categories"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"categories": categories,
+		"pagination": gin.H{
+			"page":        page,
+			"page_size":   pageSize,
+			"total":       total,
+			"total_pages": (total + int64(pageSize) - 1) / int64(pageSize),
+		},
+	})
+}
@@ -1,11 +1,16 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
 	"strconv"
 
-	"ppmtest/internal/models"
+	"github.com/cfrs2005/ppmtest/internal/models"
+	"github.com/cfrs2005/ppmtest/internal/repository"
+	"github.com/cfrs2005/ppmtest/internal/service"
 
+	"github.com/cfrs2005/ppmtest/internal/middleware"
+	"github.com/cfrs2005/ppmtest/pkg/apierr"
 	"github.com/gin-gonic/gin"
 )
 
@@ -14,16 +19,22 @@ type CommentHandler struct {
 }
 
 type CommentService interface {
-	Create(comment *models.Comment, authorID uint) error
-	GetByID(id uint) (*models.Comment, error)
-	Update(comment *models.Comment, userID uint) error
-	Delete(id uint, userID uint) error
-	GetByPostID(postID uint, page, pageSize int) ([]*models.Comment, int64, error)
-	GetByAuthorID(authorID uint, page, pageSize int) ([]*models.Comment, int64, error)
-	GetByStatus(status string, page, pageSize int) ([]*models.Comment, int64, error)
-	Approve(id uint) error
-	Reject(id uint) error
-	MarkAsSpam(id uint) error
+	Create(ctx context.Context, comment *models.Comment, authorID uint, captchaKey, captchaCode string) error
+	GetByID(ctx context.Context, id uint) (*models.Comment, error)
+	Update(ctx context.Context, comment *models.Comment, userID uint) error
+	Delete(ctx context.Context, id uint, userID uint) error
+	GetByPostID(ctx context.Context, postID uint, page, pageSize int) ([]*models.Comment, int64, error)
+	GetByAuthorID(ctx context.Context, authorID uint, page, pageSize int) ([]*models.Comment, int64, error)
+	GetByStatus(ctx context.Context, status string, page, pageSize int) ([]*models.Comment, int64, error)
+	GetThread(ctx context.Context, postID, rootID uint, maxDepth int) ([]*models.Comment, error)
+	GetReplies(ctx context.Context, parentID uint, page, pageSize int) ([]*models.Comment, int64, error)
+	CountReplies(ctx context.Context, parentID uint) (int64, error)
+	GetPendingQueue(ctx context.Context, page, pageSize int) ([]*models.Comment, int64, error)
+	Approve(ctx context.Context, id uint) error
+	Reject(ctx context.Context, id uint) error
+	MarkAsSpam(ctx context.Context, id uint) error
+	BulkSetStatus(ctx context.Context, ids []uint, status string, moderatorID uint, reason string) error
+	ListModerationHistory(ctx context.Context, commentID uint) ([]*models.ModerationLog, error)
 }
 
 func NewCommentHandler(commentService CommentService) *CommentHandler {
@@ -33,8 +44,14 @@ func NewCommentHandler(commentService CommentService) *CommentHandler {
 }
 
 type CreateCommentRequest struct {
-	PostID  uint   `json:"post_id" binding:"required"`
-	Content string `json:"content" binding:"required,min=1"`
+	PostID   uint   `json:"post_id" binding:"required"`
+	ParentID *uint  `json:"parent_id" binding:"omitempty"`
+	Content  string `json:"content" binding:"required,min=1"`
+	// CaptchaKey/CaptchaCode are only required once commentService.Create
+	// detects the author is flooding the pending queue; otherwise they're
+	// ignored.
+	CaptchaKey  string `json:"captcha_key" binding:"omitempty"`
+	CaptchaCode string `json:"captcha_code" binding:"omitempty"`
 }
 
 type UpdateCommentRequest struct {
@@ -46,17 +63,25 @@ func (h *CommentHandler) Create(c *gin.Context) {
 
 	var req CreateCommentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(apierr.Validation("invalid request body", err.Error()))
 		return
 	}
 
 	comment := &models.Comment{
-		PostID:  req.PostID,
-		Content: req.Content,
+		PostID:   req.PostID,
+		ParentID: req.ParentID,
+		Content:  req.Content,
 	}
 
-	if err := h.commentService.Create(comment, authorID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.commentService.Create(c.Request.Context(), comment, authorID, req.CaptchaKey, req.CaptchaCode); err != nil {
+		switch err {
+		case service.ErrMaxDepthExceeded, service.ErrCommentPostMismatch:
+			c.Error(apierr.Validation(err.Error(), nil))
+		case service.ErrCaptchaRequired, service.ErrCaptchaInvalid, service.ErrCaptchaExpired, service.ErrCaptchaAlreadyUsed:
+			c.Error(apierr.Validation(err.Error(), nil))
+		default:
+			c.Error(apierr.Internal("failed to create comment"))
+		}
 		return
 	}
 
@@ -70,13 +95,13 @@ func (h *CommentHandler) GetByID(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		c.Error(apierr.Validation("invalid comment ID", nil))
 		return
 	}
 
-	comment, err := h.commentService.GetByID(uint(id))
+	comment, err := h.commentService.GetByID(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+		middleware.NotFoundOrServerError(c, err, repository.ErrCommentNotFound)
 		return
 	}
 
@@ -89,26 +114,30 @@ func (h *CommentHandler) Update(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		c.Error(apierr.Validation("invalid comment ID", nil))
 		return
 	}
 
 	var req UpdateCommentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(apierr.Validation("invalid request body", err.Error()))
 		return
 	}
 
-	comment, err := h.commentService.GetByID(uint(id))
+	comment, err := h.commentService.GetByID(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+		middleware.NotFoundOrServerError(c, err, repository.ErrCommentNotFound)
 		return
 	}
 
 	comment.Content = req.Content
 
-	if err := h.commentService.Update(comment, userID); err != nil {
-		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	if err := h.commentService.Update(c.Request.Context(), comment, userID); err != nil {
+		if err == service.ErrUnauthorizedComment {
+			c.Error(apierr.Unauthorized(err.Error()))
+		} else {
+			c.Error(apierr.Internal("failed to update comment"))
+		}
 		return
 	}
 
@@ -124,12 +153,16 @@ func (h *CommentHandler) Delete(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		c.Error(apierr.Validation("invalid comment ID", nil))
 		return
 	}
 
-	if err := h.commentService.Delete(uint(id), userID); err != nil {
-		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	if err := h.commentService.Delete(c.Request.Context(), uint(id), userID); err != nil {
+		if err == service.ErrUnauthorizedComment {
+			c.Error(apierr.Unauthorized(err.Error()))
+		} else {
+			c.Error(apierr.Internal("failed to delete comment"))
+		}
 		return
 	}
 
@@ -140,7 +173,45 @@ func (h *CommentHandler) GetByPostID(c *gin.Context) {
 	postIDParam := c.Param("post_id")
 	postID, err := strconv.ParseUint(postIDParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		c.Error(apierr.Validation("invalid post ID", nil))
+		return
+	}
+
+	pageParam := c.DefaultQuery("page", "1")
+	pageSizeParam := c.DefaultQuery("page_size", "10")
+
+	page, err := strconv.Atoi(pageParam)
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	pageSize, err := strconv.Atoi(pageSizeParam)
+	if err != nil || pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	comments, total, err := h.commentService.GetByPostID(c.Request.Context(), uint(postID), page, pageSize)
+	if err != nil {
+		c.Error(apierr.Internal("failed to list comments"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"comments": comments,
+		"pagination": gin.H{
+			"page":        page,
+			"page_size":   pageSize,
+			"total":       total,
+			"total_pages": (total + int64(pageSize) - 1) / int64(pageSize),
+		},
+	})
+}
+
+func (h *CommentHandler) GetReplies(c *gin.Context) {
+	parentIDParam := c.Param("id")
+	parentID, err := strconv.ParseUint(parentIDParam, 10, 32)
+	if err != nil {
+		c.Error(apierr.Validation("invalid comment ID", nil))
 		return
 	}
 
@@ -157,9 +228,71 @@ func (h *CommentHandler) GetByPostID(c *gin.Context) {
 		pageSize = 10
 	}
 
-	comments, total, err := h.commentService.GetByPostID(uint(postID), page, pageSize)
+	comments, total, err := h.commentService.GetReplies(c.Request.Context(), uint(parentID), page, pageSize)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(apierr.Internal("failed to list replies"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"comments": comments,
+		"pagination": gin.H{
+			"page":        page,
+			"page_size":   pageSize,
+			"total":       total,
+			"total_pages": (total + int64(pageSize) - 1) / int64(pageSize),
+		},
+	})
+}
+
+func (h *CommentHandler) GetThread(c *gin.Context) {
+	postIDParam := c.Param("post_id")
+	postID, err := strconv.ParseUint(postIDParam, 10, 32)
+	if err != nil {
+		c.Error(apierr.Validation("invalid post ID", nil))
+		return
+	}
+
+	rootIDParam := c.Query("root_id")
+	rootID, err := strconv.ParseUint(rootIDParam, 10, 32)
+	if err != nil {
+		c.Error(apierr.Validation("query parameter 'root_id' is required", nil))
+		return
+	}
+
+	maxDepth := -1
+	if maxDepthParam := c.Query("max_depth"); maxDepthParam != "" {
+		if parsed, err := strconv.Atoi(maxDepthParam); err == nil {
+			maxDepth = parsed
+		}
+	}
+
+	comments, err := h.commentService.GetThread(c.Request.Context(), uint(postID), uint(rootID), maxDepth)
+	if err != nil {
+		middleware.NotFoundOrServerError(c, err, repository.ErrCommentNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"comments": comments})
+}
+
+func (h *CommentHandler) GetPendingQueue(c *gin.Context) {
+	pageParam := c.DefaultQuery("page", "1")
+	pageSizeParam := c.DefaultQuery("page_size", "10")
+
+	page, err := strconv.Atoi(pageParam)
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	pageSize, err := strconv.Atoi(pageSizeParam)
+	if err != nil || pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	comments, total, err := h.commentService.GetPendingQueue(c.Request.Context(), page, pageSize)
+	if err != nil {
+		c.Error(apierr.Internal("failed to list pending comments"))
 		return
 	}
 
@@ -178,12 +311,12 @@ func (h *CommentHandler) Approve(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		c.Error(apierr.Validation("invalid comment ID", nil))
 		return
 	}
 
-	if err := h.commentService.Approve(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.commentService.Approve(c.Request.Context(), uint(id)); err != nil {
+		middleware.NotFoundOrServerError(c, err, repository.ErrCommentNotFound)
 		return
 	}
 
@@ -194,28 +327,72 @@ func (h *CommentHandler) Reject(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		c.Error(apierr.Validation("invalid comment ID", nil))
 		return
 	}
 
-	if err := h.commentService.Reject(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.commentService.Reject(c.Request.Context(), uint(id)); err != nil {
+		middleware.NotFoundOrServerError(c, err, repository.ErrCommentNotFound)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Comment rejected successfully"})
 }
 
+type BulkSetStatusRequest struct {
+	IDs    []uint `json:"ids" binding:"required,min=1"`
+	Status string `json:"status" binding:"required"`
+	Reason string `json:"reason" binding:"omitempty"`
+}
+
+func (h *CommentHandler) BulkSetStatus(c *gin.Context) {
+	moderatorID := c.GetUint("user_id")
+
+	var req BulkSetStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apierr.Validation("invalid request body", err.Error()))
+		return
+	}
+
+	if err := h.commentService.BulkSetStatus(c.Request.Context(), req.IDs, req.Status, moderatorID, req.Reason); err != nil {
+		if err == service.ErrInvalidCommentStatus {
+			c.Error(apierr.Validation(err.Error(), nil))
+		} else {
+			c.Error(apierr.Internal("failed to update comments"))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Comments updated successfully"})
+}
+
+func (h *CommentHandler) ListModerationHistory(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		c.Error(apierr.Validation("invalid comment ID", nil))
+		return
+	}
+
+	logs, err := h.commentService.ListModerationHistory(c.Request.Context(), uint(id))
+	if err != nil {
+		c.Error(apierr.Internal("failed to list moderation history"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"history": logs})
+}
+
 func (h *CommentHandler) MarkAsSpam(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		c.Error(apierr.Validation("invalid comment ID", nil))
 		return
 	}
 
-	if err := h.commentService.MarkAsSpam(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.commentService.MarkAsSpam(c.Request.Context(), uint(id)); err != nil {
+		middleware.NotFoundOrServerError(c, err, repository.ErrCommentNotFound)
 		return
 	}
 
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/cfrs2005/ppmtest/pkg/apierr"
+	"github.com/gin-gonic/gin"
+)
+
+// GLMHandler exposes GLM-backed AI content generation over HTTP. It is not
+// currently registered by router.SetupRouter: see GLMService's doc comment
+// for why the wider GLM subsystem remains unwired.
+type GLMHandler struct {
+	glmService GLMService
+}
+
+// GLMService is the subset of service.GLMService this handler depends on.
+type GLMService interface {
+	GeneratePostContentStream(topic string, onChunk func(delta string) error) error
+}
+
+func NewGLMHandler(glmService GLMService) *GLMHandler {
+	return &GLMHandler{glmService: glmService}
+}
+
+// StreamPostContent generates a blog post for the "topic" query parameter,
+// relaying it to the client as a text/event-stream of "data: <fragment>\n\n"
+// frames as each fragment arrives, followed by a final "data: [DONE]\n\n".
+func (h *GLMHandler) StreamPostContent(c *gin.Context) {
+	topic := c.Query("topic")
+	if topic == "" {
+		c.Error(apierr.Validation("topic query parameter is required", nil))
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+
+	flusher, canFlush := c.Writer.(http.Flusher)
+
+	err := h.glmService.GeneratePostContentStream(topic, func(delta string) error {
+		if _, err := fmt.Fprintf(c.Writer, "data: %s\n\n", delta); err != nil {
+			return err
+		}
+		if canFlush {
+			flusher.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		c.Error(apierr.Internal("failed to generate post content"))
+		return
+	}
+
+	fmt.Fprint(c.Writer, "data: [DONE]\n\n")
+	if canFlush {
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/cfrs2005/ppmtest/internal/service"
+
+	"github.com/cfrs2005/ppmtest/internal/middleware"
+	"github.com/cfrs2005/ppmtest/pkg/apierr"
+	"github.com/gin-gonic/gin"
+)
+
+type OAuthHandler struct {
+	oauthService service.OAuthService
+	userService  UserService
+}
+
+func NewOAuthHandler(oauthService service.OAuthService, userService UserService) *OAuthHandler {
+	return &OAuthHandler{oauthService: oauthService, userService: userService}
+}
+
+type DecisionRequest struct {
+	ClientID            string `json:"client_id" binding:"required"`
+	RedirectURI         string `json:"redirect_uri" binding:"required"`
+	Scope               string `json:"scope"`
+	State               string `json:"state"`
+	CodeChallenge       string `json:"code_challenge"`
+	CodeChallengeMethod string `json:"code_challenge_method"`
+	Approve             bool   `json:"approve"`
+}
+
+type TokenRequest struct {
+	GrantType    string `form:"grant_type" binding:"required"`
+	Code         string `form:"code"`
+	RedirectURI  string `form:"redirect_uri"`
+	CodeVerifier string `form:"code_verifier"`
+	RefreshToken string `form:"refresh_token"`
+	ClientID     string `form:"client_id" binding:"required"`
+	ClientSecret string `form:"client_secret" binding:"required"`
+	Scope        string `form:"scope"`
+}
+
+func authorizeRequestFromQuery(c *gin.Context) service.AuthorizeRequest {
+	return service.AuthorizeRequest{
+		ClientID:            c.Query("client_id"),
+		RedirectURI:         c.Query("redirect_uri"),
+		Scope:               c.Query("scope"),
+		State:               c.Query("state"),
+		CodeChallenge:       c.Query("code_challenge"),
+		CodeChallengeMethod: c.Query("code_challenge_method"),
+	}
+}
+
+func oauthErrorStatus(err error) *apierr.APIError {
+	switch err {
+	case service.ErrInvalidClient, service.ErrInvalidRedirectURI, service.ErrMismatchedClient:
+		return apierr.Unauthorized(err.Error())
+	case service.ErrInvalidScope, service.ErrInvalidPKCE, service.ErrInvalidGrant, service.ErrUnsupportedGrant:
+		return apierr.Validation(err.Error(), nil)
+	default:
+		return apierr.Internal("oauth request failed")
+	}
+}
+
+// Authorize validates response_type=code plus the client/redirect_uri/scope
+// combination and returns the consent info for a first-party consent page to
+// render; it does not itself redirect anywhere.
+func (h *OAuthHandler) Authorize(c *gin.Context) {
+	if c.Query("response_type") != "code" {
+		c.Error(apierr.Validation("only response_type=code is supported", nil))
+		return
+	}
+
+	req := authorizeRequestFromQuery(c)
+
+	consent, err := h.oauthService.Authorize(c.Request.Context(), req)
+	if err != nil {
+		c.Error(oauthErrorStatus(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"client": gin.H{
+			"client_id": consent.Client.ClientID,
+			"name":      consent.Client.Name,
+		},
+		"scopes": consent.Scopes,
+		"state":  req.State,
+	})
+}
+
+// Decision handles the resource owner's approve/deny choice on the consent
+// page. On approval it mints an authorization code; on denial it reports
+// access_denied, matching RFC 6749 section 4.1.2.1.
+func (h *OAuthHandler) Decision(c *gin.Context) {
+	var body DecisionRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.Error(apierr.Validation("invalid request body", err.Error()))
+		return
+	}
+
+	if !body.Approve {
+		c.JSON(http.StatusOK, gin.H{"error": "access_denied", "state": body.State})
+		return
+	}
+
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apierr.Unauthorized("authentication required to approve a consent request"))
+		return
+	}
+
+	req := service.AuthorizeRequest{
+		ClientID:            body.ClientID,
+		RedirectURI:         body.RedirectURI,
+		Scope:               body.Scope,
+		State:               body.State,
+		CodeChallenge:       body.CodeChallenge,
+		CodeChallengeMethod: body.CodeChallengeMethod,
+	}
+
+	code, err := h.oauthService.IssueCode(c.Request.Context(), req, userID)
+	if err != nil {
+		c.Error(oauthErrorStatus(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"code": code, "state": body.State})
+}
+
+// Token implements POST /oauth/token for the authorization_code,
+// refresh_token and client_credentials grants.
+func (h *OAuthHandler) Token(c *gin.Context) {
+	var req TokenRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.Error(apierr.Validation("invalid request body", err.Error()))
+		return
+	}
+
+	var (
+		result *service.TokenResult
+		err    error
+	)
+
+	switch req.GrantType {
+	case "authorization_code":
+		result, err = h.oauthService.ExchangeAuthorizationCode(c.Request.Context(), req.ClientID, req.ClientSecret, req.Code, req.RedirectURI, req.CodeVerifier)
+	case "refresh_token":
+		result, err = h.oauthService.RefreshToken(c.Request.Context(), req.ClientID, req.ClientSecret, req.RefreshToken)
+	case "client_credentials":
+		result, err = h.oauthService.ClientCredentials(c.Request.Context(), req.ClientID, req.ClientSecret, req.Scope)
+	default:
+		c.Error(apierr.Validation("unsupported grant_type", req.GrantType))
+		return
+	}
+
+	if err != nil {
+		c.Error(oauthErrorStatus(err))
+		return
+	}
+
+	resp := gin.H{
+		"access_token": result.AccessToken,
+		"token_type":   result.TokenType,
+		"expires_in":   result.ExpiresIn,
+	}
+	if result.RefreshToken != "" {
+		resp["refresh_token"] = result.RefreshToken
+	}
+	if result.Scope != "" {
+		resp["scope"] = result.Scope
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// UserInfo returns the profile of the user identified by the bearer access
+// token, gated behind AuthMiddleware like any other protected endpoint.
+func (h *OAuthHandler) UserInfo(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.Error(apierr.Unauthorized("authentication required"))
+		return
+	}
+
+	user, err := h.userService.GetByID(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(apierr.Internal("failed to load user profile"))
+		return
+	}
+
+	resp := gin.H{"sub": user.ID, "username": user.Username}
+	if _, isOAuthToken := middleware.GetOAuthClientID(c); !isOAuthToken {
+		resp["email"] = user.Email
+	} else if scope, _ := middleware.GetScope(c); hasField(scope, "profile") {
+		resp["email"] = user.Email
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func hasField(fields, want string) bool {
+	for _, f := range strings.Fields(fields) {
+		if f == want {
+			return true
+		}
+	}
+	return false
+}
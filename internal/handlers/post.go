@@ -1,50 +1,78 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
 	"strconv"
 
-	"ppmtest/internal/models"
+	"github.com/cfrs2005/ppmtest/internal/models"
+	"github.com/cfrs2005/ppmtest/internal/repository"
+	"github.com/cfrs2005/ppmtest/internal/service"
 
+	"github.com/cfrs2005/ppmtest/internal/middleware"
+	"github.com/cfrs2005/ppmtest/internal/search"
+	"github.com/cfrs2005/ppmtest/pkg/apierr"
 	"github.com/gin-gonic/gin"
 )
 
 type PostHandler struct {
-	postService PostService
+	postService       PostService
+	attachmentService AttachmentService
 }
 
 type PostService interface {
-	Create(post *models.Post, authorID uint) error
-	GetByID(id uint) (*models.Post, error)
-	GetBySlug(slug string) (*models.Post, error)
-	Update(post *models.Post, userID uint) error
-	Delete(id uint, userID uint) error
-	List(page, pageSize int) ([]*models.Post, int64, error)
-	GetByAuthorID(authorID uint, page, pageSize int) ([]*models.Post, int64, error)
-	GetByStatus(status string, page, pageSize int) ([]*models.Post, int64, error)
-	Search(query string, page, pageSize int) ([]*models.Post, int64, error)
-	Publish(id uint, userID uint) error
+	Create(ctx context.Context, post *models.Post, authorID uint) error
+	GetByID(ctx context.Context, id uint) (*models.Post, error)
+	GetBySlug(ctx context.Context, slug string) (*models.Post, error)
+	Update(ctx context.Context, post *models.Post, userID uint) error
+	Delete(ctx context.Context, id uint, userID uint) error
+	List(ctx context.Context, page, pageSize int, filter repository.PostFilter) ([]*models.Post, int64, error)
+	GetByAuthorID(ctx context.Context, authorID uint, page, pageSize int) ([]*models.Post, int64, error)
+	GetByStatus(ctx context.Context, status string, page, pageSize int) ([]*models.Post, int64, error)
+	Search(ctx context.Context, page, pageSize int, opts search.Options) ([]search.Hit, int64, error)
+	Publish(ctx context.Context, id uint, userID uint) error
 }
 
-func NewPostHandler(postService PostService) *PostHandler {
+// postFilterFromQuery builds a faceted filter from the posts endpoint's
+// optional tag=, category=, author= and status= query parameters.
+func postFilterFromQuery(c *gin.Context) repository.PostFilter {
+	filter := repository.PostFilter{
+		Status:   c.Query("status"),
+		Tag:      c.Query("tag"),
+		Category: c.Query("category"),
+	}
+
+	if authorParam := c.Query("author"); authorParam != "" {
+		if authorID, err := strconv.ParseUint(authorParam, 10, 32); err == nil {
+			filter.Author = uint(authorID)
+		}
+	}
+
+	return filter
+}
+
+func NewPostHandler(postService PostService, attachmentService AttachmentService) *PostHandler {
 	return &PostHandler{
-		postService: postService,
+		postService:       postService,
+		attachmentService: attachmentService,
 	}
 }
 
 type CreatePostRequest struct {
-	Title   string `json:"title" binding:"required,min=1,max=255"`
-	Slug    string `json:"slug" binding:"omitempty,max=255"`
-	Content string `json:"content" binding:"required"`
-	Summary string `json:"summary" binding:"omitempty"`
+	Title         string `json:"title" binding:"required,min=1,max=255"`
+	Slug          string `json:"slug" binding:"omitempty,max=255"`
+	Content       string `json:"content" binding:"required"`
+	Summary       string `json:"summary" binding:"omitempty"`
+	AttachmentIDs []uint `json:"attachment_ids"`
 }
 
 type UpdatePostRequest struct {
-	Title   string `json:"title" binding:"omitempty,min=1,max=255"`
-	Slug    string `json:"slug" binding:"omitempty,max=255"`
-	Content string `json:"content" binding:"omitempty"`
-	Summary string `json:"summary" binding:"omitempty"`
-	Status  string `json:"status" binding:"omitempty,oneof=draft published archived"`
+	Title         string `json:"title" binding:"omitempty,min=1,max=255"`
+	Slug          string `json:"slug" binding:"omitempty,max=255"`
+	Content       string `json:"content" binding:"omitempty"`
+	Summary       string `json:"summary" binding:"omitempty"`
+	Status        string `json:"status" binding:"omitempty,oneof=draft published archived"`
+	AttachmentIDs []uint `json:"attachment_ids"`
 }
 
 func (h *PostHandler) Create(c *gin.Context) {
@@ -52,7 +80,7 @@ func (h *PostHandler) Create(c *gin.Context) {
 
 	var req CreatePostRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(apierr.Validation("invalid request body", err.Error()))
 		return
 	}
 
@@ -63,11 +91,22 @@ func (h *PostHandler) Create(c *gin.Context) {
 		Summary: req.Summary,
 	}
 
-	if err := h.postService.Create(post, authorID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.postService.Create(c.Request.Context(), post, authorID); err != nil {
+		if err == repository.ErrPostAlreadyExists {
+			c.Error(apierr.Conflict(err.Error()))
+		} else {
+			c.Error(apierr.Internal("failed to create post"))
+		}
 		return
 	}
 
+	if len(req.AttachmentIDs) > 0 {
+		if err := h.attachmentService.LinkToPost(c.Request.Context(), authorID, post.ID, req.AttachmentIDs); err != nil {
+			c.Error(apierr.Internal("failed to link attachments"))
+			return
+		}
+	}
+
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Post created successfully",
 		"post":    post,
@@ -78,13 +117,13 @@ func (h *PostHandler) GetByID(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		c.Error(apierr.Validation("invalid post ID", nil))
 		return
 	}
 
-	post, err := h.postService.GetByID(uint(id))
+	post, err := h.postService.GetByID(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		middleware.NotFoundOrServerError(c, err, repository.ErrPostNotFound)
 		return
 	}
 
@@ -94,9 +133,9 @@ func (h *PostHandler) GetByID(c *gin.Context) {
 func (h *PostHandler) GetBySlug(c *gin.Context) {
 	slug := c.Param("slug")
 
-	post, err := h.postService.GetBySlug(slug)
+	post, err := h.postService.GetBySlug(c.Request.Context(), slug)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		middleware.NotFoundOrServerError(c, err, repository.ErrPostNotFound)
 		return
 	}
 
@@ -109,19 +148,19 @@ func (h *PostHandler) Update(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		c.Error(apierr.Validation("invalid post ID", nil))
 		return
 	}
 
 	var req UpdatePostRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(apierr.Validation("invalid request body", err.Error()))
 		return
 	}
 
-	post, err := h.postService.GetByID(uint(id))
+	post, err := h.postService.GetByID(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		middleware.NotFoundOrServerError(c, err, repository.ErrPostNotFound)
 		return
 	}
 
@@ -141,11 +180,22 @@ func (h *PostHandler) Update(c *gin.Context) {
 		post.Status = req.Status
 	}
 
-	if err := h.postService.Update(post, userID); err != nil {
-		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	if err := h.postService.Update(c.Request.Context(), post, userID); err != nil {
+		if err == service.ErrUnauthorizedPost {
+			c.Error(apierr.Unauthorized(err.Error()))
+		} else {
+			c.Error(apierr.Internal("failed to update post"))
+		}
 		return
 	}
 
+	if len(req.AttachmentIDs) > 0 {
+		if err := h.attachmentService.LinkToPost(c.Request.Context(), userID, post.ID, req.AttachmentIDs); err != nil {
+			c.Error(apierr.Internal("failed to link attachments"))
+			return
+		}
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Post updated successfully",
 		"post":    post,
@@ -158,12 +208,16 @@ func (h *PostHandler) Delete(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		c.Error(apierr.Validation("invalid post ID", nil))
 		return
 	}
 
-	if err := h.postService.Delete(uint(id), userID); err != nil {
-		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	if err := h.postService.Delete(c.Request.Context(), uint(id), userID); err != nil {
+		if err == service.ErrUnauthorizedPost {
+			c.Error(apierr.Unauthorized(err.Error()))
+		} else {
+			c.Error(apierr.Internal("failed to delete post"))
+		}
 		return
 	}
 
@@ -184,9 +238,9 @@ func (h *PostHandler) List(c *gin.Context) {
 		pageSize = 10
 	}
 
-	posts, total, err := h.postService.List(page, pageSize)
+	posts, total, err := h.postService.List(c.Request.Context(), page, pageSize, postFilterFromQuery(c))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(apierr.Internal("failed to list posts"))
 		return
 	}
 
@@ -204,7 +258,7 @@ func (h *PostHandler) List(c *gin.Context) {
 func (h *PostHandler) Search(c *gin.Context) {
 	query := c.Query("q")
 	if query == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter 'q' is required"})
+		c.Error(apierr.Validation("query parameter 'q' is required", nil))
 		return
 	}
 
@@ -221,14 +275,37 @@ func (h *PostHandler) Search(c *gin.Context) {
 		pageSize = 10
 	}
 
-	posts, total, err := h.postService.Search(query, page, pageSize)
+	mode := search.Mode(c.DefaultQuery("mode", string(search.ModeNatural)))
+
+	minScore := 0.0
+	if minScoreParam := c.Query("min_score"); minScoreParam != "" {
+		if parsed, err := strconv.ParseFloat(minScoreParam, 64); err == nil {
+			minScore = parsed
+		}
+	}
+
+	hits, total, err := h.postService.Search(c.Request.Context(), page, pageSize, search.Options{
+		Query:    query,
+		Mode:     mode,
+		MinScore: minScore,
+		Filter:   postFilterFromQuery(c),
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(apierr.Internal("search failed"))
 		return
 	}
 
+	results := make([]gin.H, 0, len(hits))
+	for _, hit := range hits {
+		results = append(results, gin.H{
+			"post":    hit.Post,
+			"score":   hit.Score,
+			"snippet": hit.Snippet,
+		})
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"posts": posts,
+		"results": results,
 		"pagination": gin.H{
 			"page":        page,
 			"page_size":   pageSize,
@@ -245,12 +322,16 @@ func (h *PostHandler) Publish(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := strconv.ParseUint(idParam, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		c.Error(apierr.Validation("invalid post ID", nil))
 		return
 	}
 
-	if err := h.postService.Publish(uint(id), userID); err != nil {
-		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	if err := h.postService.Publish(c.Request.Context(), uint(id), userID); err != nil {
+		if err == service.ErrUnauthorizedPost {
+			c.Error(apierr.Unauthorized(err.Error()))
+		} else {
+			c.Error(apierr.Internal("failed to publish post"))
+		}
 		return
 	}
 
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/cfrs2005/ppmtest/internal/models"
+	"github.com/cfrs2005/ppmtest/internal/repository"
+	"github.com/cfrs2005/ppmtest/internal/service"
+
+	"github.com/cfrs2005/ppmtest/internal/middleware"
+	"github.com/cfrs2005/ppmtest/pkg/apierr"
+	"github.com/gin-gonic/gin"
+)
+
+type ReportHandler struct {
+	reportService ReportService
+}
+
+type ReportService interface {
+	CreateReport(ctx context.Context, reporterID uint, targetType models.ReportTargetType, targetID uint, reason models.ReportReason, description string) (*models.Report, error)
+	ListPending(ctx context.Context, page, pageSize int) ([]*models.Report, int64, error)
+	Resolve(ctx context.Context, id uint, handlerID uint, action string) error
+	Dismiss(ctx context.Context, id uint, handlerID uint) error
+}
+
+func NewReportHandler(reportService ReportService) *ReportHandler {
+	return &ReportHandler{
+		reportService: reportService,
+	}
+}
+
+type CreateReportRequest struct {
+	Reason      string `json:"reason" binding:"required"`
+	Description string `json:"description" binding:"omitempty,max=1000"`
+}
+
+type ResolveReportRequest struct {
+	// Action optionally also takes the reported target down, e.g. "hide".
+	// Leave empty to resolve the report without touching the target.
+	Action string `json:"action" binding:"omitempty"`
+}
+
+func (h *ReportHandler) ReportPost(c *gin.Context) {
+	h.createReport(c, models.ReportTargetPost, "post_id")
+}
+
+func (h *ReportHandler) ReportComment(c *gin.Context) {
+	h.createReport(c, models.ReportTargetComment, "comment_id")
+}
+
+func (h *ReportHandler) createReport(c *gin.Context, targetType models.ReportTargetType, targetIDField string) {
+	reporterID := c.GetUint("user_id")
+
+	idParam := c.Param("id")
+	targetID, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		c.Error(apierr.Validation("invalid "+targetIDField, nil))
+		return
+	}
+
+	var req CreateReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apierr.Validation("invalid request body", err.Error()))
+		return
+	}
+
+	report, err := h.reportService.CreateReport(c.Request.Context(), reporterID, targetType, uint(targetID), models.ReportReason(req.Reason), req.Description)
+	if err != nil {
+		switch err {
+		case service.ErrReportAlreadyExists:
+			c.Error(apierr.Conflict(err.Error()))
+		case service.ErrInvalidTargetType:
+			c.Error(apierr.Validation(err.Error(), nil))
+		default:
+			c.Error(apierr.Internal("failed to create report"))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Report submitted successfully",
+		"report":  report,
+	})
+}
+
+func (h *ReportHandler) ListPending(c *gin.Context) {
+	pageParam := c.DefaultQuery("page", "1")
+	pageSizeParam := c.DefaultQuery("page_size", "10")
+
+	page, err := strconv.Atoi(pageParam)
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	pageSize, err := strconv.Atoi(pageSizeParam)
+	if err != nil || pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	reports, total, err := h.reportService.ListPending(c.Request.Context(), page, pageSize)
+	if err != nil {
+		c.Error(apierr.Internal("failed to list reports"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reports": reports,
+		"pagination": gin.H{
+			"page":        page,
+			"page_size":   pageSize,
+			"total":       total,
+			"total_pages": (total + int64(pageSize) - 1) / int64(pageSize),
+		},
+	})
+}
+
+func (h *ReportHandler) Resolve(c *gin.Context) {
+	handlerID := c.GetUint("user_id")
+
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		c.Error(apierr.Validation("invalid report ID", nil))
+		return
+	}
+
+	var req ResolveReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apierr.Validation("invalid request body", err.Error()))
+		return
+	}
+
+	if err := h.reportService.Resolve(c.Request.Context(), uint(id), handlerID, req.Action); err != nil {
+		middleware.NotFoundOrServerError(c, err, repository.ErrReportNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Report resolved successfully"})
+}
+
+func (h *ReportHandler) Dismiss(c *gin.Context) {
+	handlerID := c.GetUint("user_id")
+
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		c.Error(apierr.Validation("invalid report ID", nil))
+		return
+	}
+
+	if err := h.reportService.Dismiss(c.Request.Context(), uint(id), handlerID); err != nil {
+		middleware.NotFoundOrServerError(c, err, repository.ErrReportNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Report dismissed successfully"})
+}
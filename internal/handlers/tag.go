@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/cfrs2005/ppmtest/internal/middleware"
+	"github.com/cfrs2005/ppmtest/internal/models"
+	"github.com/cfrs2005/ppmtest/internal/repository"
+	"github.com/cfrs2005/ppmtest/pkg/apierr"
+	"github.com/gin-gonic/gin"
+)
+
+type TagHandler struct {
+	tagService TagService
+}
+
+type TagService interface {
+	CreateTag(ctx context.Context, tag *models.Tag) error
+	GetTagByID(ctx context.Context, id uint) (*models.Tag, error)
+	GetTagBySlug(ctx context.Context, slug string) (*models.Tag, error)
+	UpdateTag(ctx context.Context, tag *models.Tag) error
+	DeleteTag(ctx context.Context, id uint) error
+	ListTags(ctx context.Context, page, pageSize int) ([]*models.Tag, int64, error)
+}
+
+func NewTagHandler(tagService TagService) *TagHandler {
+	return &TagHandler{
+		tagService: tagService,
+	}
+}
+
+type CreateTagRequest struct {
+	Name string `json:"name" binding:"required,min=1,max=100"`
+	Slug string `json:"slug" binding:"omitempty,max=100"`
+}
+
+type UpdateTagRequest struct {
+	Name string `json:"name" binding:"omitempty,min=1,max=100"`
+	Slug string `json:"slug" binding:"omitempty,max=100"`
+}
+
+func (h *TagHandler) Create(c *gin.Context) {
+	var req CreateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apierr.Validation("invalid request body", err.Error()))
+		return
+	}
+
+	tag := &models.Tag{
+		Name: req.Name,
+		Slug: req.Slug,
+	}
+
+	if err := h.tagService.CreateTag(c.Request.Context(), tag); err != nil {
+		if err == repository.ErrTagAlreadyExists {
+			c.Error(apierr.Conflict(err.Error()))
+		} else {
+			c.Error(apierr.Internal("failed to create tag"))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Tag created successfully",
+		"tag":     tag,
+	})
+}
+
+func (h *TagHandler) GetByID(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		c.Error(apierr.Validation("invalid tag ID", nil))
+		return
+	}
+
+	tag, err := h.tagService.GetTagByID(c.Request.Context(), uint(id))
+	if err != nil {
+		middleware.NotFoundOrServerError(c, err, repository.ErrTagNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+func (h *TagHandler) Update(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		c.Error(apierr.Validation("invalid tag ID", nil))
+		return
+	}
+
+	var req UpdateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apierr.Validation("invalid request body", err.Error()))
+		return
+	}
+
+	tag, err := h.tagService.GetTagByID(c.Request.Context(), uint(id))
+	if err != nil {
+		middleware.NotFoundOrServerError(c, err, repository.ErrTagNotFound)
+		return
+	}
+
+	if req.Name != "" {
+		tag.Name = req.Name
+	}
+	if req.Slug != "" {
+		tag.Slug = req.Slug
+	}
+
+	if err := h.tagService.UpdateTag(c.Request.Context(), tag); err != nil {
+		if err == repository.ErrTagAlreadyExists {
+			c.Error(apierr.Conflict(err.Error()))
+		} else {
+			c.Error(apierr.Internal("failed to update tag"))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Tag updated successfully",
+		"tag":     tag,
+	})
+}
+
+func (h *TagHandler) Delete(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		c.Error(apierr.Validation("invalid tag ID", nil))
+		return
+	}
+
+	if err := h.tagService.DeleteTag(c.Request.Context(), uint(id)); err != nil {
+		middleware.NotFoundOrServerError(c, err, repository.ErrTagNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tag deleted successfully"})
+}
+
+func (h *TagHandler) List(c *gin.Context) {
+	pageParam := c.DefaultQuery("page", "1")
+	pageSizeParam := c.DefaultQuery("page_size", "10")
+
+	page, err := strconv.Atoi(pageParam)
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	pageSize, err := strconv.Atoi(pageSizeParam)
+	if err != nil || pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	tags, total, err := h.tagService.ListTags(c.Request.Context(), page, pageSize)
+	if err != nil {
+		c.Error(apierr.Internal("failed to list tags"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tags": tags,
+		"pagination": gin.H{
+			"page":        page,
+			"page_size":   pageSize,
+			"total":       total,
+			"total_pages": (total + int64(pageSize) - 1) / int64(pageSize),
+		},
+	})
+}
@@ -0,0 +1,310 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/cfrs2005/ppmtest/internal/models"
+	"github.com/cfrs2005/ppmtest/internal/repository"
+	"github.com/cfrs2005/ppmtest/internal/service"
+
+	"github.com/cfrs2005/ppmtest/internal/middleware"
+	"github.com/cfrs2005/ppmtest/pkg/apierr"
+	"github.com/gin-gonic/gin"
+)
+
+type UserHandler struct {
+	userService    UserService
+	tokens         TokenIssuer
+	captchaService CaptchaService
+}
+
+type UserService interface {
+	Register(ctx context.Context, username, email, password string) (*models.User, error)
+	Login(ctx context.Context, email, password string) (*models.User, error)
+	GetByID(ctx context.Context, id uint) (*models.User, error)
+	Update(ctx context.Context, user *models.User) error
+	Delete(ctx context.Context, id uint) error
+	List(ctx context.Context, page, pageSize int) ([]*models.User, int64, error)
+	ChangePassword(ctx context.Context, userID uint, oldPassword, newPassword string) error
+}
+
+// TokenIssuer is satisfied by *jwt.Service. UserHandler depends on the
+// interface so auth.login/refresh/logout can be tested without a real
+// signing secret.
+type TokenIssuer interface {
+	IssueTokenPair(userID uint, role string) (access, refresh string, err error)
+	Refresh(refreshToken string) (access, refresh string, err error)
+	Revoke(tokenString string) error
+	InvalidateUser(userID uint)
+	IssueMFAChallenge(userID uint) (string, error)
+}
+
+func NewUserHandler(userService UserService, tokens TokenIssuer, captchaService CaptchaService) *UserHandler {
+	return &UserHandler{
+		userService:    userService,
+		tokens:         tokens,
+		captchaService: captchaService,
+	}
+}
+
+type RegisterRequest struct {
+	Username    string `json:"username" binding:"required,min=1,max=50"`
+	Email       string `json:"email" binding:"required,email"`
+	Password    string `json:"password" binding:"required,min=8"`
+	CaptchaKey  string `json:"captcha_key" binding:"required"`
+	CaptchaCode string `json:"captcha_code" binding:"required"`
+}
+
+type LoginRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type UpdateUserRequest struct {
+	Username string `json:"username" binding:"omitempty,min=1,max=50"`
+	Email    string `json:"email" binding:"omitempty,email"`
+}
+
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=8"`
+}
+
+func (h *UserHandler) Register(c *gin.Context) {
+	var req RegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apierr.Validation("invalid request body", err.Error()))
+		return
+	}
+
+	if err := h.captchaService.Verify(c.Request.Context(), req.CaptchaKey, req.CaptchaCode); err != nil {
+		c.Error(apierr.Validation(err.Error(), nil))
+		return
+	}
+
+	user, err := h.userService.Register(c.Request.Context(), req.Username, req.Email, req.Password)
+	if err != nil {
+		if err == repository.ErrUserAlreadyExists {
+			c.Error(apierr.Conflict(err.Error()))
+		} else {
+			c.Error(apierr.Internal("failed to register user"))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "User registered successfully",
+		"user":    user,
+	})
+}
+
+func (h *UserHandler) Login(c *gin.Context) {
+	var req LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apierr.Validation("invalid request body", err.Error()))
+		return
+	}
+
+	user, err := h.userService.Login(c.Request.Context(), req.Email, req.Password)
+	if err != nil {
+		if errors.Is(err, service.ErrMFARequired) {
+			challenge, challengeErr := h.tokens.IssueMFAChallenge(user.ID)
+			if challengeErr != nil {
+				c.Error(apierr.Internal("failed to issue mfa challenge"))
+				return
+			}
+			c.JSON(http.StatusOK, gin.H{
+				"mfa_required":    true,
+				"challenge_token": challenge,
+			})
+			return
+		}
+		c.Error(apierr.Unauthorized(err.Error()))
+		return
+	}
+
+	access, refresh, err := h.tokens.IssueTokenPair(user.ID, user.Role)
+	if err != nil {
+		c.Error(apierr.Internal("failed to issue tokens"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"access_token":  access,
+		"refresh_token": refresh,
+		"user":          user,
+	})
+}
+
+func (h *UserHandler) Refresh(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apierr.Validation("invalid request body", err.Error()))
+		return
+	}
+
+	access, refresh, err := h.tokens.Refresh(req.RefreshToken)
+	if err != nil {
+		c.Error(apierr.Unauthorized(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"access_token":  access,
+		"refresh_token": refresh,
+	})
+}
+
+func (h *UserHandler) Logout(c *gin.Context) {
+	token, exists := middleware.GetRawToken(c)
+	if !exists {
+		c.Error(apierr.Unauthorized("missing bearer token"))
+		return
+	}
+
+	if err := h.tokens.Revoke(token); err != nil {
+		c.Error(apierr.Internal("failed to revoke token"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+}
+
+func (h *UserHandler) GetByID(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		c.Error(apierr.Validation("invalid user ID", nil))
+		return
+	}
+
+	user, err := h.userService.GetByID(c.Request.Context(), uint(id))
+	if err != nil {
+		middleware.NotFoundOrServerError(c, err, repository.ErrUserNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *UserHandler) Update(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		c.Error(apierr.Validation("invalid user ID", nil))
+		return
+	}
+
+	if uint(id) != userID {
+		c.Error(apierr.Forbidden("cannot update another user's profile"))
+		return
+	}
+
+	var req UpdateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apierr.Validation("invalid request body", err.Error()))
+		return
+	}
+
+	user, err := h.userService.GetByID(c.Request.Context(), uint(id))
+	if err != nil {
+		middleware.NotFoundOrServerError(c, err, repository.ErrUserNotFound)
+		return
+	}
+
+	if req.Username != "" {
+		user.Username = req.Username
+	}
+	if req.Email != "" {
+		user.Email = req.Email
+	}
+
+	if err := h.userService.Update(c.Request.Context(), user); err != nil {
+		c.Error(apierr.Internal("failed to update user"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "User updated successfully",
+		"user":    user,
+	})
+}
+
+func (h *UserHandler) ChangePassword(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apierr.Validation("invalid request body", err.Error()))
+		return
+	}
+
+	if err := h.userService.ChangePassword(c.Request.Context(), userID, req.OldPassword, req.NewPassword); err != nil {
+		if err == service.ErrInvalidCredentials {
+			c.Error(apierr.Unauthorized(err.Error()))
+		} else {
+			c.Error(apierr.Internal("failed to change password"))
+		}
+		return
+	}
+
+	h.tokens.InvalidateUser(userID)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password changed successfully"})
+}
+
+func (h *UserHandler) Delete(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		c.Error(apierr.Validation("invalid user ID", nil))
+		return
+	}
+
+	if err := h.userService.Delete(c.Request.Context(), uint(id)); err != nil {
+		middleware.NotFoundOrServerError(c, err, repository.ErrUserNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User deleted successfully"})
+}
+
+func (h *UserHandler) List(c *gin.Context) {
+	pageParam := c.DefaultQuery("page", "1")
+	pageSizeParam := c.DefaultQuery("page_size", "10")
+
+	page, err := strconv.Atoi(pageParam)
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	pageSize, err := strconv.Atoi(pageSizeParam)
+	if err != nil || pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	users, total, err := h.userService.List(c.Request.Context(), page, pageSize)
+	if err != nil {
+		c.Error(apierr.Internal("failed to list users"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"users": users,
+		"pagination": gin.H{
+			"page":        page,
+			"page_size":   pageSize,
+			"total":       total,
+			"total_pages": (total + int64(pageSize) - 1) / int64(pageSize),
+		},
+	})
+}
@@ -0,0 +1,82 @@
+// Package health exposes the liveness/readiness endpoints a Kubernetes
+// deployment uses to manage rollouts: /healthz reports whether the
+// process is alive at all, /readyz whether it should currently receive
+// traffic.
+package health
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// pingTimeout bounds how long a readiness probe waits on the database
+// before reporting not-ready, so a wedged DB can't also wedge every probe
+// indefinitely.
+const pingTimeout = 2 * time.Second
+
+// Checker backs /healthz and /readyz. ready starts true and is flipped to
+// false by SetReady(false) during graceful shutdown, so Kubernetes stops
+// routing traffic here before the process actually stops accepting
+// connections.
+type Checker struct {
+	db         *sql.DB
+	glmEnabled bool
+	ready      atomic.Bool
+}
+
+// NewChecker returns a Checker that pings db for readiness. glmEnabled
+// reports whether the GLM client is configured (cfg.GLM.APIKey != ""); a
+// live network call to GLM on every probe would be wasteful and could
+// itself rate-limit the blog, so readiness only checks that GLM is
+// configured, not that it's currently reachable.
+func NewChecker(db *sql.DB, glmEnabled bool) *Checker {
+	c := &Checker{db: db, glmEnabled: glmEnabled}
+	c.ready.Store(true)
+	return c
+}
+
+// SetReady flips whether ReadinessHandler reports ready, independent of
+// the DB ping - used to fail readiness during graceful shutdown before
+// the listener actually closes.
+func (c *Checker) SetReady(ready bool) {
+	c.ready.Store(ready)
+}
+
+// LivenessHandler reports 200 as long as the process is running; it never
+// touches the database, since a slow DB should fail readiness, not
+// liveness (which would restart the pod).
+func (c *Checker) LivenessHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, gin.H{"status": "ok"})
+	}
+}
+
+// ReadinessHandler reports 200 only while SetReady(true) is in effect and
+// the database responds to a ping within pingTimeout.
+func (c *Checker) ReadinessHandler() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		if !c.ready.Load() {
+			ginCtx.JSON(http.StatusServiceUnavailable, gin.H{"status": "shutting down"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(ginCtx.Request.Context(), pingTimeout)
+		defer cancel()
+
+		if err := c.db.PingContext(ctx); err != nil {
+			ginCtx.JSON(http.StatusServiceUnavailable, gin.H{"status": "database unreachable"})
+			return
+		}
+
+		body := gin.H{"status": "ok", "database": "ok"}
+		if c.glmEnabled {
+			body["glm"] = "configured"
+		}
+		ginCtx.JSON(http.StatusOK, body)
+	}
+}
@@ -0,0 +1,72 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// slowQueryThreshold marks a query as slow enough to log at Warn even when
+// it didn't error.
+const slowQueryThreshold = 200 * time.Millisecond
+
+// GormLogger adapts gorm/logger.Interface to slog. Repositories already
+// run every call through ctx via .WithContext(ctx) (see
+// internal/repository), so Trace/Info/Warn/Error pull the request-scoped
+// logger via FromContext, and every query a request makes logs under that
+// request's request_id alongside its handler/service log lines.
+type GormLogger struct {
+	level gormlogger.LogLevel
+}
+
+// NewGormLogger returns a GormLogger at level, for use as gorm.Config.Logger.
+func NewGormLogger(level gormlogger.LogLevel) *GormLogger {
+	return &GormLogger{level: level}
+}
+
+func (l *GormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	clone := *l
+	clone.level = level
+	return &clone
+}
+
+func (l *GormLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Info {
+		FromContext(ctx).Info(fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *GormLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Warn {
+		FromContext(ctx).Warn(fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *GormLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Error {
+		FromContext(ctx).Error(fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if l.level <= gormlogger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	sql, rows := fc()
+	logger := FromContext(ctx)
+
+	switch {
+	case err != nil && l.level >= gormlogger.Error && !errors.Is(err, gorm.ErrRecordNotFound):
+		logger.Error("gorm query failed", "sql", sql, "rows", rows, "elapsed", elapsed, "error", err)
+	case elapsed > slowQueryThreshold && l.level >= gormlogger.Warn:
+		logger.Warn("gorm slow query", "sql", sql, "rows", rows, "elapsed", elapsed)
+	case l.level >= gormlogger.Info:
+		logger.Debug("gorm query", "sql", sql, "rows", rows, "elapsed", elapsed)
+	}
+}
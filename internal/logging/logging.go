@@ -0,0 +1,90 @@
+// Package logging builds the process's structured logger (stdlib
+// log/slog) from config.LoggingConfig, and threads a per-request instance
+// of it through context.Context so repository/service code, GORM's query
+// log (see gorm.go), and the HTTP access log (see
+// internal/middleware.LoggerMiddleware) can all be grepped by the same
+// request_id.
+//
+// This was asked for as zap or slog. zap isn't vendored in this tree's
+// module cache (no network access to fetch it), and Go 1.21's stdlib
+// log/slog already covers structured JSON/console output and levels, so
+// this builds on slog directly and hand-rolls only the one piece neither
+// stdlib nor this tree's dependencies provide: per-level sampling, see
+// sampling.go.
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"runtime/debug"
+
+	"github.com/cfrs2005/ppmtest/internal/config"
+)
+
+type ctxKey int
+
+const loggerCtxKey ctxKey = iota
+
+// New builds a *slog.Logger from cfg: Format selects a JSON (default) or
+// "console" text handler, Level parses "debug"/"info"/"warn"/"error"
+// (default "info"), and Sampling, if set, caps how many repeated
+// lower-severity records get through per second.
+func New(cfg config.LoggingConfig) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: parseLevel(cfg.Level)}
+
+	var handler slog.Handler
+	if cfg.Format == "console" {
+		handler = slog.NewTextHandler(os.Stdout, opts)
+	} else {
+		handler = slog.NewJSONHandler(os.Stdout, opts)
+	}
+
+	if cfg.Sampling != nil {
+		handler = newSamplingHandler(handler, cfg.Sampling.Initial, cfg.Sampling.Thereafter)
+	}
+
+	return slog.New(handler)
+}
+
+func parseLevel(level string) slog.Level {
+	switch level {
+	case "debug":
+		return slog.LevelDebug
+	case "warn":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// WithLogger returns a copy of ctx carrying logger, so code that only has a
+// context.Context - a repository/service call, GORM's logger.Interface -
+// can still log through the same request-scoped *slog.Logger the HTTP
+// middleware attached.
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerCtxKey, logger)
+}
+
+// FromContext returns the logger WithLogger attached to ctx, or
+// slog.Default() if none was attached, so logging from outside a request
+// (startup, a background job) still works instead of panicking.
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerCtxKey).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// Fatal logs msg at error level with a stack trace attached, then exits
+// the process with status 1. Use in place of log.Fatalf for startup
+// failures: slog has no built-in equivalent, and an operator debugging a
+// crashed deploy benefits from a stack trace without having to reproduce
+// it first.
+func Fatal(logger *slog.Logger, msg string, args ...any) {
+	attrs := append(append([]any{}, args...), "stack", string(debug.Stack()))
+	logger.Error(msg, attrs...)
+	os.Exit(1)
+}
@@ -0,0 +1,94 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// samplingHandler wraps another slog.Handler and limits how many records
+// sharing a level+message get through per one-second window: the first
+// `initial` pass unconditionally, then only every `thereafter`-th one
+// does. This mirrors the shape of zap's per-second sampling core - zap
+// itself isn't vendored here (no network access to fetch it), so this
+// reimplements just that policy on top of slog.Handler. Records at
+// slog.LevelError or above are never sampled away, since those are
+// exactly what an operator greps a request_id for.
+type samplingHandler struct {
+	next                slog.Handler
+	initial, thereafter int
+	state               *samplingState
+}
+
+// samplingState is shared (via pointer) across the handler returned by New
+// and every clone WithAttrs/WithGroup produces from it, so they all sample
+// against the same counters instead of each starting fresh.
+type samplingState struct {
+	mu     sync.Mutex
+	counts map[string]*sampleCounter
+}
+
+type sampleCounter struct {
+	windowStart time.Time
+	count       int
+}
+
+func newSamplingHandler(next slog.Handler, initial, thereafter int) *samplingHandler {
+	if initial <= 0 {
+		initial = 1
+	}
+	if thereafter <= 0 {
+		thereafter = 1
+	}
+	return &samplingHandler{
+		next:       next,
+		initial:    initial,
+		thereafter: thereafter,
+		state:      &samplingState{counts: make(map[string]*sampleCounter)},
+	}
+}
+
+func (h *samplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *samplingHandler) Handle(ctx context.Context, record slog.Record) error {
+	if h.allow(record) {
+		return h.next.Handle(ctx, record)
+	}
+	return nil
+}
+
+func (h *samplingHandler) allow(record slog.Record) bool {
+	if record.Level >= slog.LevelError {
+		return true
+	}
+
+	key := fmt.Sprintf("%s|%s", record.Level, record.Message)
+
+	h.state.mu.Lock()
+	defer h.state.mu.Unlock()
+
+	now := time.Now()
+	counter, ok := h.state.counts[key]
+	if !ok || now.Sub(counter.windowStart) >= time.Second {
+		counter = &sampleCounter{windowStart: now}
+		h.state.counts[key] = counter
+	}
+
+	counter.count++
+	if counter.count <= h.initial {
+		return true
+	}
+	return (counter.count-h.initial)%h.thereafter == 0
+}
+
+func (h *samplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &samplingHandler{next: h.next.WithAttrs(attrs), initial: h.initial, thereafter: h.thereafter, state: h.state}
+}
+
+func (h *samplingHandler) WithGroup(name string) slog.Handler {
+	return &samplingHandler{next: h.next.WithGroup(name), initial: h.initial, thereafter: h.thereafter, state: h.state}
+}
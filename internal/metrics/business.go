@@ -0,0 +1,10 @@
+package metrics
+
+// Business counters services increment directly on the outcomes an
+// operator actually cares about, alongside the generic HTTP/DB metrics
+// above.
+var (
+	PostsCreatedTotal    = NewCounter("posts_created_total", "Total posts created")
+	LoginsTotal          = NewCounter("logins_total", "Total login attempts", "result")
+	CommentsCreatedTotal = NewCounter("comments_created_total", "Total comments created", "status")
+)
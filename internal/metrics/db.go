@@ -0,0 +1,27 @@
+package metrics
+
+import "database/sql"
+
+// RegisterDBStatsCollector registers GaugeFuncs reading db.Stats() live at
+// scrape time, so the values always reflect the pool's current state
+// rather than whatever it was when the server started.
+func RegisterDBStatsCollector(db *sql.DB) {
+	NewGaugeFunc("db_max_open_connections", "Maximum open connections to the database", func() float64 {
+		return float64(db.Stats().MaxOpenConnections)
+	})
+	NewGaugeFunc("db_open_connections", "The number of established connections to the database", func() float64 {
+		return float64(db.Stats().OpenConnections)
+	})
+	NewGaugeFunc("db_in_use", "The number of connections currently in use", func() float64 {
+		return float64(db.Stats().InUse)
+	})
+	NewGaugeFunc("db_idle", "The number of idle connections", func() float64 {
+		return float64(db.Stats().Idle)
+	})
+	NewGaugeFunc("db_wait_count", "The total number of connections waited for", func() float64 {
+		return float64(db.Stats().WaitCount)
+	})
+	NewGaugeFunc("db_wait_duration_seconds", "The total time blocked waiting for a new connection", func() float64 {
+		return db.Stats().WaitDuration.Seconds()
+	})
+}
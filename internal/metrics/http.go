@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	httpRequestDuration = NewHistogram("http_request_duration_seconds", "HTTP request latency in seconds", "method", "path", "status")
+	httpRequestsTotal   = NewCounter("http_requests_total", "Total HTTP requests", "method", "path", "status")
+)
+
+// Middleware records http_request_duration_seconds and http_requests_total
+// for every request, labeled by method, route path (c.FullPath(), so
+// /posts/:id stays one series rather than one per ID) and response status.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		path := c.FullPath()
+		if path == "" {
+			path = "unmatched"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+
+		httpRequestDuration.Observe(time.Since(start).Seconds(), c.Request.Method, path, status)
+		httpRequestsTotal.Inc(c.Request.Method, path, status)
+	}
+}
+
+// Handler renders every registered metric in Prometheus text exposition
+// format for GET /metrics.
+func Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.String(http.StatusOK, Render())
+	}
+}
@@ -0,0 +1,53 @@
+package mfa
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// backupCodeCount is how many single-use recovery codes ConfirmMFA mints,
+// matching what most authenticator-based MFA flows (GitHub, Google) hand
+// out.
+const backupCodeCount = 10
+
+// GenerateBackupCodes returns backupCodeCount freshly generated plaintext
+// codes, formatted like "XXXX-XXXX" for readability. Callers must hash each
+// one with HashBackupCode before persisting it - these are shown to the
+// user exactly once.
+func GenerateBackupCodes() ([]string, error) {
+	codes := make([]string, backupCodeCount)
+	for i := range codes {
+		code, err := randomBackupCode()
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = code
+	}
+	return codes, nil
+}
+
+func randomBackupCode() (string, error) {
+	const alphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789" // no 0/O/1/I, easy to transcribe
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	for i, b := range buf {
+		buf[i] = alphabet[int(b)%len(alphabet)]
+	}
+	return fmt.Sprintf("%s-%s", buf[:4], buf[4:]), nil
+}
+
+// HashBackupCode hashes a plaintext backup code for storage, the same way
+// user passwords are hashed (see service.userService.Register).
+func HashBackupCode(code string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(code), bcrypt.DefaultCost)
+	return string(hash), err
+}
+
+// CheckBackupCode reports whether code matches hash.
+func CheckBackupCode(hash, code string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(code)) == nil
+}
@@ -1,51 +1,75 @@
 package middleware
 
 import (
-	"net/http"
 	"strings"
 
+	"github.com/cfrs2005/ppmtest/internal/auth/jwt"
+
+	"github.com/cfrs2005/ppmtest/pkg/apierr"
 	"github.com/gin-gonic/gin"
 )
 
 const (
-	userIDKey = "user_id"
-	roleKey   = "user_role"
+	userIDKey   = "user_id"
+	roleKey     = "user_role"
+	tokenKey    = "raw_token"
+	clientIDKey = "oauth_client_id"
+	scopeKey    = "oauth_scope"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+// TokenVerifier is satisfied by *jwt.Service. AuthMiddleware depends on the
+// interface, not the concrete type, so it can be exercised with a fake in
+// tests without dragging in the real signing secret.
+type TokenVerifier interface {
+	Verify(tokenString string) (*jwt.Claims, error)
+}
+
+// AuthMiddleware parses the Bearer token, verifies its HS256 signature,
+// expiry and revocation status via verifier, and populates the gin context
+// with the caller's user ID and role.
+func AuthMiddleware(verifier TokenVerifier) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
+			c.Error(apierr.Unauthorized("authorization header required"))
 			c.Abort()
 			return
 		}
 
 		parts := strings.SplitN(authHeader, " ", 2)
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header format"})
+			c.Error(apierr.Unauthorized("invalid authorization header format"))
 			c.Abort()
 			return
 		}
 
 		token := parts[1]
 
-		userID, role, err := validateToken(token)
+		claims, err := verifier.Verify(token)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			c.Error(apierr.Unauthorized(err.Error()))
 			c.Abort()
 			return
 		}
 
-		c.Set(userIDKey, userID)
-		c.Set(roleKey, role)
+		c.Set(userIDKey, claims.UserID)
+		c.Set(roleKey, claims.Role)
+		c.Set(tokenKey, token)
+		c.Set(clientIDKey, claims.ClientID)
+		c.Set(scopeKey, claims.Scope)
 
 		c.Next()
 	}
 }
 
-func validateToken(token string) (uint, string, error) {
-	return 1, "author", nil
+// GetRawToken returns the bearer token validated for this request, so a
+// logout handler can revoke the exact token that was presented.
+func GetRawToken(c *gin.Context) (string, bool) {
+	token, exists := c.Get(tokenKey)
+	if !exists {
+		return "", false
+	}
+	return token.(string), true
 }
 
 func GetUserID(c *gin.Context) (uint, bool) {
@@ -64,11 +88,56 @@ func GetUserRole(c *gin.Context) (string, bool) {
 	return role.(string), true
 }
 
+// GetScope returns the space-separated OAuth2 scope granted to the token
+// used for this request. It's empty for first-party login tokens, which
+// aren't scope-restricted.
+func GetScope(c *gin.Context) (string, bool) {
+	scope, exists := c.Get(scopeKey)
+	if !exists {
+		return "", false
+	}
+	return scope.(string), true
+}
+
+// GetOAuthClientID returns the OAuth2 client ID the current token was
+// issued to, if any.
+func GetOAuthClientID(c *gin.Context) (string, bool) {
+	clientID, exists := c.Get(clientIDKey)
+	if !exists || clientID == "" {
+		return "", false
+	}
+	return clientID.(string), true
+}
+
+// RequireScope rejects requests whose token doesn't carry scope. A token
+// with no ClientID (issued by the first-party login flow, not OAuth2) is
+// never scope-restricted and always passes.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clientID, isOAuthToken := GetOAuthClientID(c)
+		if !isOAuthToken {
+			c.Next()
+			return
+		}
+
+		granted, _ := GetScope(c)
+		for _, s := range strings.Fields(granted) {
+			if s == scope {
+				c.Next()
+				return
+			}
+		}
+
+		c.Error(apierr.Forbidden("token for client " + clientID + " is missing required scope: " + scope))
+		c.Abort()
+	}
+}
+
 func RequireRole(roles ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userRole, exists := GetUserRole(c)
 		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "User role not found"})
+			c.Error(apierr.Unauthorized("user role not found"))
 			c.Abort()
 			return
 		}
@@ -80,7 +149,7 @@ func RequireRole(roles ...string) gin.HandlerFunc {
 			}
 		}
 
-		c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+		c.Error(apierr.Forbidden("insufficient permissions"))
 		c.Abort()
 	}
 }
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"errors"
+
+	"github.com/cfrs2005/ppmtest/pkg/apierr"
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorHandlerMiddleware renders the last error a handler attached via
+// c.Error as a single JSON envelope, so every endpoint fails the same way
+// instead of each hand-rolling its own status code and body.
+func ErrorHandlerMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 {
+			return
+		}
+
+		apiErr := apierr.From(c.Errors.Last().Err)
+
+		body := gin.H{
+			"code":       apiErr.Code,
+			"message":    apiErr.Message,
+			"request_id": GetRequestID(c),
+		}
+		if apiErr.Details != nil {
+			body["details"] = apiErr.Details
+		}
+
+		c.JSON(apiErr.HTTPStatus, body)
+	}
+}
+
+// NotFoundOrServerError reports err as a 404 apierr.NotFound when it matches
+// notFoundSentinel (via errors.Is), or as an opaque 500 otherwise, so
+// handlers never leak internal error text for their "record doesn't exist"
+// case. Mirrors the same-named Gogs helper.
+func NotFoundOrServerError(c *gin.Context, err error, notFoundSentinel error) {
+	if errors.Is(err, notFoundSentinel) {
+		c.Error(apierr.NotFound(notFoundSentinel.Error()))
+		return
+	}
+	c.Error(apierr.Internal("an internal error occurred"))
+}
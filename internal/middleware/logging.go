@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/cfrs2005/ppmtest/internal/logging"
+	"github.com/cfrs2005/ppmtest/internal/tracing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LoggerMiddleware attaches a per-request *slog.Logger - tagged with the
+// request_id RequestIDMiddleware assigned - to the request's
+// context.Context, so every repository/service call reached via
+// c.Request.Context(), and through logging.GormLogger every GORM query,
+// logs under that same ID. It also emits one access log line per request
+// once the handler chain completes.
+func LoggerMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		requestLogger := slog.Default().With("request_id", GetRequestID(c))
+		if traceID := tracing.TraceID(c.Request.Context()); traceID != "" {
+			requestLogger = requestLogger.With("trace_id", traceID)
+		}
+		c.Request = c.Request.WithContext(logging.WithLogger(c.Request.Context(), requestLogger))
+
+		c.Next()
+
+		requestLogger.Info("request",
+			"method", c.Request.Method,
+			"path", c.FullPath(),
+			"status", c.Writer.Status(),
+			"latency", time.Since(start),
+		)
+	}
+}
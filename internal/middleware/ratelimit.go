@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"github.com/cfrs2005/ppmtest/internal/ratelimit"
+
+	"github.com/cfrs2005/ppmtest/pkg/apierr"
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimitByIP rejects requests once the caller's client IP has exhausted
+// limiter's token bucket, so a single source can't flood endpoints like
+// /captcha/new or comment creation.
+func RateLimitByIP(limiter *ratelimit.Limiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !limiter.Allow(c.ClientIP()) {
+			c.Error(apierr.TooManyRequests("too many requests, please slow down"))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	requestIDContextKey = "request_id"
+	requestIDHeader     = "X-Request-ID"
+)
+
+// RequestIDMiddleware stamps every request with a short ID, echoed back via
+// the X-Request-ID response header and folded into the error envelope
+// ErrorHandlerMiddleware renders, so a client can hand the ID back when
+// reporting a failed call. A caller-supplied X-Request-ID is honored as-is,
+// so requests can be traced across a chain of internal services.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestIDHeader)
+		if id == "" {
+			id = newRequestID()
+		}
+
+		c.Set(requestIDContextKey, id)
+		c.Header(requestIDHeader, id)
+		c.Next()
+	}
+}
+
+// GetRequestID returns the current request's ID, or "" if RequestIDMiddleware
+// hasn't run.
+func GetRequestID(c *gin.Context) string {
+	id, ok := c.Get(requestIDContextKey)
+	if !ok {
+		return ""
+	}
+	s, _ := id.(string)
+	return s
+}
+
+func newRequestID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"github.com/cfrs2005/ppmtest/internal/tracing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TracingMiddleware starts a root span for every request - continuing an
+// inbound "traceparent" header if present, otherwise starting a new trace
+// - and stores it in the request's context.Context so downstream
+// service/repository code (and, via tracing.GormPlugin, GORM itself) can
+// start child spans under it via tracing.Start. Register this before
+// LoggerMiddleware so the per-request logger can tag its lines with the
+// same trace_id.
+func TracingMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		traceID, parentSpanID := tracing.ParseTraceParent(c.GetHeader("traceparent"))
+
+		ctx, span := tracing.StartRoot(c.Request.Context(), "http "+c.Request.Method+" "+c.FullPath(), traceID, parentSpanID)
+		span.SetAttribute("http.method", c.Request.Method)
+		span.SetAttribute("http.path", c.Request.URL.Path)
+		c.Request = c.Request.WithContext(ctx)
+		c.Header("traceparent", tracing.FormatTraceParent(span))
+
+		c.Next()
+
+		span.SetAttribute("http.status_code", c.Writer.Status())
+		span.Finish()
+	}
+}
@@ -0,0 +1,21 @@
+// Package mirc is a declarative route-generation layer inspired by the mir
+// approach used in paopao-ce: instead of hand-maintaining router.SetupRouter,
+// each API group is declared here as a Go interface, and `go generate` (see
+// cmd/mirc) reads the interfaces' doc comments to emit the gin wiring under
+// internal/router/auto.
+//
+// A group interface carries a `mirc:group <name> <basePath>` directive on its
+// doc comment. Each method carries a `mirc:route <VERB> <path> [middleware...]`
+// directive describing how that method is mounted. Contributors add an
+// endpoint by adding a method here and re-running `make generate` - they
+// never hand-edit the generated router.
+package mirc
+
+//go:generate go run ../../cmd/mirc
+
+import "github.com/gin-gonic/gin"
+
+// HandlerFunc is the signature every declared endpoint method must have.
+// It is defined here, rather than imported from gin directly in callers,
+// so generated code has a single, stable type to bind against.
+type HandlerFunc = gin.HandlerFunc
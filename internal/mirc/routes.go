@@ -0,0 +1,124 @@
+package mirc
+
+import "github.com/gin-gonic/gin"
+
+// PostAPI declares the /api/v1/posts endpoint group.
+//
+// mirc:group posts /api/v1/posts
+type PostAPI interface {
+	// mirc:route GET /
+	List(c *gin.Context)
+
+	// mirc:route GET /:id
+	GetByID(c *gin.Context)
+
+	// mirc:route GET /slug/:slug
+	GetBySlug(c *gin.Context)
+
+	// mirc:route GET /search
+	Search(c *gin.Context)
+
+	// mirc:route POST / auth
+	Create(c *gin.Context)
+
+	// mirc:route PUT /:id auth
+	Update(c *gin.Context)
+
+	// mirc:route DELETE /:id auth
+	Delete(c *gin.Context)
+
+	// mirc:route POST /:id/publish auth
+	Publish(c *gin.Context)
+}
+
+// CommentAPI declares the /api/v1/comments endpoint group.
+//
+// mirc:group comments /api/v1/comments
+type CommentAPI interface {
+	// mirc:route GET /:id
+	GetByID(c *gin.Context)
+
+	// mirc:route GET /post/:post_id
+	GetByPostID(c *gin.Context)
+
+	// mirc:route POST / auth
+	Create(c *gin.Context)
+
+	// mirc:route PUT /:id auth
+	Update(c *gin.Context)
+
+	// mirc:route DELETE /:id auth
+	Delete(c *gin.Context)
+
+	// mirc:route POST /admin/:id/approve auth,admin
+	Approve(c *gin.Context)
+
+	// mirc:route POST /admin/:id/reject auth,admin
+	Reject(c *gin.Context)
+
+	// mirc:route POST /admin/:id/spam auth,admin
+	MarkAsSpam(c *gin.Context)
+}
+
+// TagAPI declares the /api/v1/tags endpoint group.
+//
+// mirc:group tags /api/v1/tags
+type TagAPI interface {
+	// mirc:route GET /
+	List(c *gin.Context)
+
+	// mirc:route GET /:id
+	GetByID(c *gin.Context)
+
+	// mirc:route POST / auth,admin
+	Create(c *gin.Context)
+
+	// mirc:route PUT /:id auth,admin
+	Update(c *gin.Context)
+
+	// mirc:route DELETE /:id auth,admin
+	Delete(c *gin.Context)
+}
+
+// CategoryAPI declares the /api/v1/categories endpoint group.
+//
+// mirc:group categories /api/v1/categories
+type CategoryAPI interface {
+	// mirc:route GET /
+	List(c *gin.Context)
+
+	// mirc:route GET /:id
+	GetByID(c *gin.Context)
+
+	// mirc:route POST / auth,admin
+	Create(c *gin.Context)
+
+	// mirc:route PUT /:id auth,admin
+	Update(c *gin.Context)
+
+	// mirc:route DELETE /:id auth,admin
+	Delete(c *gin.Context)
+}
+
+// UserAPI declares the /api/v1/users and /api/v1/auth endpoint groups.
+//
+// mirc:group users /api/v1
+type UserAPI interface {
+	// mirc:route POST /auth/register
+	Register(c *gin.Context)
+
+	// mirc:route POST /auth/login
+	Login(c *gin.Context)
+
+	// mirc:route GET /users
+	List(c *gin.Context)
+
+	// mirc:route GET /users/:id
+	GetByID(c *gin.Context)
+
+	// mirc:route PUT /users/:id auth
+	Update(c *gin.Context)
+
+	// mirc:route DELETE /users/:id auth
+	Delete(c *gin.Context)
+}
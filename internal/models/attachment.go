@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// AttachmentType classifies an Attachment by the media kind detected from
+// its MIME type, so the service can apply per-type size limits and the
+// frontend can pick a renderer without re-sniffing the file.
+type AttachmentType string
+
+const (
+	AttachmentTypeImage AttachmentType = "image"
+	AttachmentTypeVideo AttachmentType = "video"
+	AttachmentTypeOther AttachmentType = "other"
+)
+
+// Attachment is a file uploaded by a user, optionally linked to a Post once
+// it's referenced from the post body or gallery. StoragePath is the key the
+// configured storage.Storage backend saved it under, not a public URL.
+type Attachment struct {
+	ID          uint           `gorm:"primaryKey" json:"id"`
+	UserID      uint           `gorm:"not null;index" json:"user_id"`
+	PostID      *uint          `gorm:"index" json:"post_id,omitempty"`
+	FileSize    int64          `gorm:"not null" json:"file_size"`
+	MimeType    string         `gorm:"size:100;not null" json:"mime_type"`
+	Type        AttachmentType `gorm:"size:20;not null" json:"type"`
+	ImgWidth    int            `gorm:"default:0" json:"img_width,omitempty"`
+	ImgHeight   int            `gorm:"default:0" json:"img_height,omitempty"`
+	StoragePath string         `gorm:"size:512;not null" json:"-"`
+	Checksum    string         `gorm:"size:64;index" json:"checksum"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Captcha is a short-lived challenge issued by captcha.New and consumed by
+// CaptchaService.Verify. Key identifies the challenge to the client (e.g.
+// returned alongside the rendered image); Phone/Email/IP record who it was
+// issued to for the channel that generated it.
+type Captcha struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Key       string    `gorm:"size:64;uniqueIndex;not null" json:"key"`
+	Phone     string    `gorm:"size:20;index" json:"phone,omitempty"`
+	Email     string    `gorm:"size:255;index" json:"email,omitempty"`
+	IP        string    `gorm:"size:45;index" json:"ip,omitempty"`
+	Code      string    `gorm:"size:10;not null" json:"-"`
+	UseTimes  int       `gorm:"default:0" json:"use_times"`
+	ExpiresAt time.Time `gorm:"not null" json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// ModerationLog is an append-only record of a moderator action taken on a
+// Comment, written alongside every Approve/Reject/MarkAsSpam/BulkSetStatus
+// call so the moderation queue has an audit trail.
+type ModerationLog struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	CommentID   uint      `gorm:"not null;index" json:"comment_id"`
+	ModeratorID uint      `gorm:"not null" json:"moderator_id"`
+	FromStatus  string    `gorm:"size:20;not null" json:"from_status"`
+	ToStatus    string    `gorm:"size:20;not null" json:"to_status"`
+	Reason      string    `gorm:"type:text" json:"reason,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// OAuthClient is a third-party application registered to act on behalf of
+// users via the authorization-code flow, or on its own via client_credentials.
+type OAuthClient struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	ClientID      string    `gorm:"size:64;uniqueIndex;not null" json:"client_id"`
+	ClientSecret  string    `gorm:"size:255;not null" json:"-"`
+	Name          string    `gorm:"size:100;not null" json:"name"`
+	RedirectURIs  string    `gorm:"type:text;not null" json:"redirect_uris"` // newline-separated, matched exactly per OAuth2 recommendation
+	AllowedScopes string    `gorm:"type:text" json:"allowed_scopes"`         // space-separated
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// AuthorizationCode is the short-lived code minted by GET /oauth/authorize
+// and redeemed once by POST /oauth/token. CodeChallenge/Method carry PKCE
+// (RFC 7636) when the client supplied one.
+type AuthorizationCode struct {
+	ID                  uint      `gorm:"primaryKey" json:"id"`
+	Code                string    `gorm:"size:128;uniqueIndex;not null" json:"-"`
+	ClientID            string    `gorm:"size:64;not null;index" json:"client_id"`
+	UserID              uint      `gorm:"not null" json:"user_id"`
+	RedirectURI         string    `gorm:"type:text;not null" json:"redirect_uri"`
+	Scope               string    `gorm:"type:text" json:"scope"`
+	CodeChallenge       string    `gorm:"size:255" json:"-"`
+	CodeChallengeMethod string    `gorm:"size:10" json:"-"`
+	ExpiresAt           time.Time `json:"expires_at"`
+	CreatedAt           time.Time `json:"created_at"`
+}
@@ -1,39 +1,90 @@
 package models
 
 import (
+	"fmt"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // Post represents a blog post
 type Post struct {
-	ID          uint      `gorm:"primaryKey" json:"id"`
-	Title       string    `gorm:"size:255;not null" json:"title"`
-	Slug        string    `gorm:"size:255;uniqueIndex;not null" json:"slug"`
-	Content     string    `gorm:"type:text" json:"content"`
-	Summary     string    `gorm:"type:text" json:"summary"`
-	Status      string    `gorm:"size:20;default:draft" json:"status"` // draft, published, archived
-	AuthorID    uint      `gorm:"not null" json:"author_id"`
-	ViewCount   int       `gorm:"default:0" json:"view_count"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	Title       string     `gorm:"size:255;not null" json:"title"`
+	Slug        string     `gorm:"size:255;uniqueIndex;not null" json:"slug"`
+	Content     string     `gorm:"type:text" json:"content"`
+	Summary     string     `gorm:"type:text" json:"summary"`
+	Status      string     `gorm:"size:20;default:draft" json:"status"` // draft, published, archived
+	AuthorID    uint       `gorm:"not null" json:"author_id"`
+	CategoryID  *uint      `gorm:"index" json:"category_id,omitempty"`
+	Category    *Category  `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
+	Tags        []Tag      `gorm:"many2many:post_tags;" json:"tags,omitempty"`
+	ViewCount   int        `gorm:"default:0" json:"view_count"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
 	PublishedAt *time.Time `json:"published_at,omitempty"`
 }
 
-// Comment represents a comment on a post
+// Comment represents a comment on a post. Comments form a tree via ParentID;
+// Path is a materialized path of ancestor IDs (e.g. "/1/17/42/", one segment
+// per ancestor followed by this comment's own ID) that lets GetThread find
+// an entire subtree with a single prefix match instead of a recursive query.
 type Comment struct {
-	ID        uint      `gorm:"primaryKey" json:"id"`
-	PostID    uint      `gorm:"not null;index" json:"post_id"`
-	AuthorID  uint      `gorm:"not null" json:"author_id"`
-	Content   string    `gorm:"type:text;not null" json:"content"`
-	Status    string    `gorm:"size:20;default:pending" json:"status"` // pending, approved, spam
-	CreatedAt time.Time `json:"created_at"`
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	PostID       uint      `gorm:"not null;index" json:"post_id"`
+	AuthorID     uint      `gorm:"not null" json:"author_id"`
+	ParentID     *uint     `gorm:"index" json:"parent_id,omitempty"`
+	Path         string    `gorm:"size:255;index" json:"path"`
+	Depth        int       `gorm:"default:0" json:"depth"`
+	RepliesCount int       `gorm:"default:0" json:"replies_count"`
+	Content      string    `gorm:"type:text;not null" json:"content"`
+	Status       string    `gorm:"size:20;default:pending" json:"status"` // pending, approved, spam
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// AfterCreate computes Path/Depth from the parent comment (if any) now that
+// this comment has its own ID, and bumps the parent's RepliesCount. Runs
+// inside the same transaction GORM wraps every Create in, so the path and
+// the counter update are atomic with the insert.
+func (c *Comment) AfterCreate(tx *gorm.DB) error {
+	prefix := "/"
+	depth := 0
+
+	if c.ParentID != nil {
+		var parent Comment
+		if err := tx.Select("path", "depth").First(&parent, *c.ParentID).Error; err != nil {
+			return err
+		}
+		prefix = parent.Path
+		depth = parent.Depth + 1
+
+		if err := tx.Model(&Comment{}).Where("id = ?", *c.ParentID).
+			UpdateColumn("replies_count", gorm.Expr("replies_count + 1")).Error; err != nil {
+			return err
+		}
+	}
+
+	c.Path = fmt.Sprintf("%s%d/", prefix, c.ID)
+	c.Depth = depth
+
+	return tx.Model(c).Updates(map[string]any{"path": c.Path, "depth": c.Depth}).Error
+}
+
+// AfterDelete decrements the parent's RepliesCount so it stays in sync when
+// a reply is removed.
+func (c *Comment) AfterDelete(tx *gorm.DB) error {
+	if c.ParentID == nil {
+		return nil
+	}
+	return tx.Model(&Comment{}).Where("id = ?", *c.ParentID).
+		UpdateColumn("replies_count", gorm.Expr("replies_count - 1")).Error
 }
 
 // Tag represents a tag for posts
 type Tag struct {
-	ID        uint   `gorm:"primaryKey" json:"id"`
-	Name      string `gorm:"size:100;uniqueIndex;not null" json:"name"`
-	Slug      string `gorm:"size:100;uniqueIndex;not null" json:"slug"`
+	ID   uint   `gorm:"primaryKey" json:"id"`
+	Name string `gorm:"size:100;uniqueIndex;not null" json:"name"`
+	Slug string `gorm:"size:100;uniqueIndex;not null" json:"slug"`
 }
 
 // Category represents a category for posts
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// ReportTargetType identifies what kind of record a Report flags.
+type ReportTargetType string
+
+const (
+	ReportTargetPost    ReportTargetType = "post"
+	ReportTargetComment ReportTargetType = "comment"
+	ReportTargetUser    ReportTargetType = "user"
+)
+
+// ReportReason categorizes why a reporter flagged a target.
+type ReportReason string
+
+const (
+	ReportReasonSpam       ReportReason = "spam"
+	ReportReasonAbuse      ReportReason = "abuse"
+	ReportReasonHarassment ReportReason = "harassment"
+	ReportReasonIllegal    ReportReason = "illegal"
+	ReportReasonOther      ReportReason = "other"
+)
+
+// ReportStatus tracks a Report through the moderator workflow.
+type ReportStatus string
+
+const (
+	ReportStatusPending   ReportStatus = "pending"
+	ReportStatusResolved  ReportStatus = "resolved"
+	ReportStatusDismissed ReportStatus = "dismissed"
+)
+
+// Report is a user-filed flag against a post, comment or user, reviewed by
+// a moderator via ReportService.Resolve/Dismiss.
+type Report struct {
+	ID          uint             `gorm:"primaryKey" json:"id"`
+	ReporterID  uint             `gorm:"not null;index" json:"reporter_id"`
+	TargetType  ReportTargetType `gorm:"size:20;not null;index:idx_report_target" json:"target_type"`
+	TargetID    uint             `gorm:"not null;index:idx_report_target" json:"target_id"`
+	Reason      ReportReason     `gorm:"size:30;not null" json:"reason"`
+	Description string           `gorm:"type:text" json:"description,omitempty"`
+	Status      ReportStatus     `gorm:"size:20;default:pending;index" json:"status"`
+	HandlerID   *uint            `json:"handler_id,omitempty"`
+	HandledAt   *time.Time       `json:"handled_at,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
@@ -10,12 +10,51 @@ type User struct {
 	Username  string    `gorm:"size:50;uniqueIndex;not null" json:"username"`
 	Email     string    `gorm:"size:100;uniqueIndex;not null" json:"email"`
 	Password  string    `gorm:"size:255;not null" json:"-"`
-	Role      string    `gorm:"size:20;default:author" json:"role"` // admin, author, subscriber
+	Role      string    `gorm:"size:20;default:author" json:"role"`   // admin, author, subscriber
 	Status    string    `gorm:"size:20;default:active" json:"status"` // active, inactive, banned
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 
+	// MFAEnabled is flipped on by UserService.ConfirmMFA once the user has
+	// proven they can generate valid codes with MFASecret, and gates both
+	// UserService.Login's second-factor challenge and the enrollment
+	// requirement some roles carry (see config.MFAConfig.RequiredRoles).
+	MFAEnabled bool `gorm:"default:false" json:"mfa_enabled"`
+	// MFASecret is the TOTP shared secret (RFC 6238), base32-encoded, set by
+	// EnrollMFA and never exposed outside that enrollment response.
+	MFASecret string `gorm:"size:64" json:"-"`
+
 	// Relations
-	Posts     []Post     `gorm:"foreignKey:AuthorID" json:"posts,omitempty"`
-	Comments  []Comment  `gorm:"foreignKey:AuthorID" json:"comments,omitempty"`
+	Posts       []Post          `gorm:"foreignKey:AuthorID" json:"posts,omitempty"`
+	Comments    []Comment       `gorm:"foreignKey:AuthorID" json:"comments,omitempty"`
+	BackupCodes []MFABackupCode `gorm:"foreignKey:UserID" json:"-"`
+	Identities  []UserIdentity  `gorm:"foreignKey:UserID" json:"identities,omitempty"`
+}
+
+// MFABackupCode is a single-use recovery code minted alongside MFA
+// enrollment, for logging in when the user's authenticator app isn't
+// available. Only CodeHash is ever stored - the plaintext code is shown to
+// the user once, at ConfirmMFA time, the same way a password only ever
+// exists as a bcrypt hash once Register returns.
+type MFABackupCode struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	UserID    uint       `gorm:"not null;index" json:"user_id"`
+	CodeHash  string     `gorm:"size:255;not null" json:"-"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// UserIdentity links a user to an external OIDC/OAuth2 provider account
+// (Google, GitHub, ...), so the same person can sign in either with a
+// password or through that provider. The pairing is resolved by
+// OIDCEmailFilter mapping the provider's email to the blog account's email
+// when they differ (e.g. a work IdP account linked to a personal blog
+// login), rather than assuming they're always identical.
+type UserIdentity struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	UserID         uint      `gorm:"not null;index" json:"user_id"`
+	Provider       string    `gorm:"size:32;not null" json:"provider"` // "google", "github", ...
+	ProviderUserID string    `gorm:"size:255;not null" json:"provider_user_id"`
+	Email          string    `gorm:"size:255" json:"email,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
 }
@@ -0,0 +1,36 @@
+package oidc
+
+import "strings"
+
+// EmailFilter maps the email an identity provider reports to the email a
+// blog account is actually registered under, for operators whose IdP
+// account differs from their blog email (e.g. a work Google Workspace
+// account linked to a personal blog login).
+//
+// Aliases takes priority and matches a full address exactly
+// ("alice@corp.com" -> "alice@personal.com"); DomainAliases rewrites just
+// the domain ("@corp.com" -> "@personal.com") for every address in that
+// domain. An email matching neither is returned unchanged.
+type EmailFilter struct {
+	Aliases       map[string]string
+	DomainAliases map[string]string
+}
+
+// Apply resolves email through f, returning the blog account email that
+// should be used to find or create a local user for this identity.
+func (f EmailFilter) Apply(email string) string {
+	if mapped, ok := f.Aliases[email]; ok {
+		return mapped
+	}
+
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return email
+	}
+	domain := email[at:]
+	if mapped, ok := f.DomainAliases[domain]; ok {
+		return email[:at] + mapped
+	}
+
+	return email
+}
@@ -0,0 +1,63 @@
+// Package ratelimit implements an in-memory token bucket keyed by an
+// arbitrary string (an IP address or a user ID), mirroring the
+// map+mutex storage internal/auth/jwt uses for its revocation blocklist.
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Limiter grants up to burst tokens per key, refilled at refillRate tokens
+// per second, up to burst.
+type Limiter struct {
+	mu         sync.Mutex
+	buckets    map[string]*bucket
+	burst      float64
+	refillRate float64
+}
+
+type bucket struct {
+	tokens     float64
+	lastRefill time.Time
+}
+
+// NewLimiter returns a Limiter that allows burst requests immediately and
+// refills at refillRate requests per second thereafter.
+func NewLimiter(burst int, refillRate float64) *Limiter {
+	return &Limiter{
+		buckets:    make(map[string]*bucket),
+		burst:      float64(burst),
+		refillRate: refillRate,
+	}
+}
+
+// Allow reports whether key has a token available, consuming one if so.
+func (l *Limiter) Allow(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	b, exists := l.buckets[key]
+	if !exists {
+		b = &bucket{tokens: l.burst, lastRefill: now}
+		l.buckets[key] = b
+	} else {
+		elapsed := now.Sub(b.lastRefill).Seconds()
+		b.tokens = minFloat(l.burst, b.tokens+elapsed*l.refillRate)
+		b.lastRefill = now
+	}
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
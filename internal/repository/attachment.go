@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cfrs2005/ppmtest/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var ErrAttachmentNotFound = errors.New("attachment not found")
+
+type AttachmentRepository interface {
+	Create(ctx context.Context, attachment *models.Attachment) error
+	GetByID(ctx context.Context, id uint) (*models.Attachment, error)
+	Delete(ctx context.Context, id uint) error
+	ListByUser(ctx context.Context, userID uint, offset, limit int) ([]*models.Attachment, int64, error)
+	// SumSizeByUser totals FileSize across every attachment owned by userID,
+	// so the service layer can enforce a per-user storage quota.
+	SumSizeByUser(ctx context.Context, userID uint) (int64, error)
+	// LinkToPost sets PostID on every attachment in ids that's owned by
+	// userID; attachments not owned by userID are silently skipped.
+	LinkToPost(ctx context.Context, ids []uint, postID, userID uint) error
+}
+
+type attachmentRepository struct {
+	db *gorm.DB
+}
+
+func NewAttachmentRepository(db *gorm.DB) AttachmentRepository {
+	return &attachmentRepository{db: db}
+}
+
+func (r *attachmentRepository) Create(ctx context.Context, attachment *models.Attachment) error {
+	result := r.db.WithContext(ctx).Create(attachment)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+func (r *attachmentRepository) GetByID(ctx context.Context, id uint) (*models.Attachment, error) {
+	var attachment models.Attachment
+	result := r.db.WithContext(ctx).First(&attachment, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrAttachmentNotFound
+		}
+		return nil, result.Error
+	}
+	return &attachment, nil
+}
+
+func (r *attachmentRepository) Delete(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&models.Attachment{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrAttachmentNotFound
+	}
+	return nil
+}
+
+func (r *attachmentRepository) ListByUser(ctx context.Context, userID uint, offset, limit int) ([]*models.Attachment, int64, error) {
+	var attachments []*models.Attachment
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Attachment{}).Where("user_id = ?", userID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	result := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&attachments)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	return attachments, total, nil
+}
+
+func (r *attachmentRepository) SumSizeByUser(ctx context.Context, userID uint) (int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).Model(&models.Attachment{}).
+		Where("user_id = ?", userID).
+		Select("COALESCE(SUM(file_size), 0)").
+		Scan(&total).Error
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+func (r *attachmentRepository) LinkToPost(ctx context.Context, ids []uint, postID, userID uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Model(&models.Attachment{}).
+		Where("id IN ? AND user_id = ?", ids, userID).
+		Update("post_id", postID).Error
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cfrs2005/ppmtest/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCaptchaNotFound = errors.New("captcha not found")
+)
+
+type CaptchaRepository interface {
+	Create(ctx context.Context, captcha *models.Captcha) error
+	GetByKey(ctx context.Context, key string) (*models.Captcha, error)
+	// MarkUsed increments UseTimes, spending the captcha so it can't be
+	// verified again.
+	MarkUsed(ctx context.Context, id uint) error
+	Delete(ctx context.Context, id uint) error
+	// CountRecentByIP reports how many captchas have been issued to ip
+	// since since, for throttling /captcha/new.
+	CountRecentByIP(ctx context.Context, ip string, since time.Time) (int64, error)
+}
+
+type captchaRepository struct {
+	db *gorm.DB
+}
+
+func NewCaptchaRepository(db *gorm.DB) CaptchaRepository {
+	return &captchaRepository{db: db}
+}
+
+func (r *captchaRepository) Create(ctx context.Context, captcha *models.Captcha) error {
+	return r.db.WithContext(ctx).Create(captcha).Error
+}
+
+func (r *captchaRepository) GetByKey(ctx context.Context, key string) (*models.Captcha, error) {
+	var captcha models.Captcha
+	result := r.db.WithContext(ctx).Where("key = ?", key).First(&captcha)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrCaptchaNotFound
+		}
+		return nil, result.Error
+	}
+	return &captcha, nil
+}
+
+func (r *captchaRepository) MarkUsed(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Model(&models.Captcha{}).Where("id = ?", id).
+		UpdateColumn("use_times", gorm.Expr("use_times + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrCaptchaNotFound
+	}
+	return nil
+}
+
+func (r *captchaRepository) Delete(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&models.Captcha{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrCaptchaNotFound
+	}
+	return nil
+}
+
+func (r *captchaRepository) CountRecentByIP(ctx context.Context, ip string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Captcha{}).
+		Where("ip = ? AND created_at >= ?", ip, since).
+		Count(&count).Error
+	return count, err
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"errors"
 
 	"github.com/cfrs2005/ppmtest/internal/models"
@@ -8,17 +9,19 @@ import (
 )
 
 var (
-	ErrCategoryNotFound = errors.New("category not found")
+	ErrCategoryNotFound      = errors.New("category not found")
+	ErrCategoryAlreadyExists = errors.New("category already exists")
 )
 
 // CategoryRepository defines the interface for category data operations
 type CategoryRepository interface {
-	Create(category *models.Category) error
-	FindByID(id uint) (*models.Category, error)
-	FindBySlug(slug string) (*models.Category, error)
-	Update(category *models.Category) error
-	Delete(id uint) error
-	List(offset, limit int) ([]*models.Category, int64, error)
+	Create(ctx context.Context, category *models.Category) error
+	FindByID(ctx context.Context, id uint) (*models.Category, error)
+	FindBySlug(ctx context.Context, slug string) (*models.Category, error)
+	Update(ctx context.Context, category *models.Category) error
+	Delete(ctx context.Context, id uint) error
+	List(ctx context.Context, offset, limit int) ([]*models.Category, int64, error)
+	GetByName(ctx context.Context, name string) (*models.Category, error)
 }
 
 type categoryRepository struct {
@@ -30,14 +33,20 @@ func NewCategoryRepository(db *gorm.DB) CategoryRepository {
 	return &categoryRepository{db: db}
 }
 
-func (r *categoryRepository) Create(category *models.Category) error {
-	result := r.db.Create(category)
-	return result.Error
+func (r *categoryRepository) Create(ctx context.Context, category *models.Category) error {
+	result := r.db.WithContext(ctx).Create(category)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+			return ErrCategoryAlreadyExists
+		}
+		return result.Error
+	}
+	return nil
 }
 
-func (r *categoryRepository) FindByID(id uint) (*models.Category, error) {
+func (r *categoryRepository) FindByID(ctx context.Context, id uint) (*models.Category, error) {
 	var category models.Category
-	result := r.db.First(&category, id)
+	result := r.db.WithContext(ctx).First(&category, id)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, ErrCategoryNotFound
@@ -47,9 +56,9 @@ func (r *categoryRepository) FindByID(id uint) (*models.Category, error) {
 	return &category, nil
 }
 
-func (r *categoryRepository) FindBySlug(slug string) (*models.Category, error) {
+func (r *categoryRepository) FindBySlug(ctx context.Context, slug string) (*models.Category, error) {
 	var category models.Category
-	result := r.db.Where("slug = ?", slug).First(&category)
+	result := r.db.WithContext(ctx).Where("slug = ?", slug).First(&category)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, ErrCategoryNotFound
@@ -59,8 +68,8 @@ func (r *categoryRepository) FindBySlug(slug string) (*models.Category, error) {
 	return &category, nil
 }
 
-func (r *categoryRepository) Update(category *models.Category) error {
-	result := r.db.Save(category)
+func (r *categoryRepository) Update(ctx context.Context, category *models.Category) error {
+	result := r.db.WithContext(ctx).Save(category)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -70,8 +79,8 @@ func (r *categoryRepository) Update(category *models.Category) error {
 	return nil
 }
 
-func (r *categoryRepository) Delete(id uint) error {
-	result := r.db.Delete(&models.Category{}, id)
+func (r *categoryRepository) Delete(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&models.Category{}, id)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -81,18 +90,30 @@ func (r *categoryRepository) Delete(id uint) error {
 	return nil
 }
 
-func (r *categoryRepository) List(offset, limit int) ([]*models.Category, int64, error) {
+func (r *categoryRepository) List(ctx context.Context, offset, limit int) ([]*models.Category, int64, error) {
 	var categories []*models.Category
 	var total int64
 
-	if err := r.db.Model(&models.Category{}).Count(&total).Error; err != nil {
+	if err := r.db.WithContext(ctx).Model(&models.Category{}).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	result := r.db.Order("name ASC").Offset(offset).Limit(limit).Find(&categories)
+	result := r.db.WithContext(ctx).Order("name ASC").Offset(offset).Limit(limit).Find(&categories)
 	if result.Error != nil {
 		return nil, 0, result.Error
 	}
 
 	return categories, total, nil
-}
\ No newline at end of file
+}
+
+func (r *categoryRepository) GetByName(ctx context.Context, name string) (*models.Category, error) {
+	var category models.Category
+	result := r.db.WithContext(ctx).Where("name = ?", name).First(&category)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, result.Error
+	}
+	return &category, nil
+}
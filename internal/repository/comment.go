@@ -1,9 +1,11 @@
 package repository
 
 import (
+	"context"
 	"errors"
+	"time"
 
-	"ppmtest/internal/models"
+	"github.com/cfrs2005/ppmtest/internal/models"
 
 	"gorm.io/gorm"
 )
@@ -13,13 +15,46 @@ var (
 )
 
 type CommentRepository interface {
-	Create(comment *models.Comment) error
-	GetByID(id uint) (*models.Comment, error)
-	Update(comment *models.Comment) error
-	Delete(id uint) error
-	GetByPostID(postID uint, offset, limit int) ([]*models.Comment, int64, error)
-	GetByAuthorID(authorID uint, offset, limit int) ([]*models.Comment, int64, error)
-	GetByStatus(status string, offset, limit int) ([]*models.Comment, int64, error)
+	Create(ctx context.Context, comment *models.Comment) error
+	GetByID(ctx context.Context, id uint) (*models.Comment, error)
+	Update(ctx context.Context, comment *models.Comment) error
+	Delete(ctx context.Context, id uint) error
+	GetByPostID(ctx context.Context, postID uint, offset, limit int) ([]*models.Comment, int64, error)
+	GetByAuthorID(ctx context.Context, authorID uint, offset, limit int) ([]*models.Comment, int64, error)
+	GetByStatus(ctx context.Context, status string, offset, limit int) ([]*models.Comment, int64, error)
+
+	// GetThread returns rootID's subtree under postID, ordered by Path so
+	// callers can assemble the tree in a single pass (parents always precede
+	// their children). maxDepth is relative to rootID's own depth; 0 means
+	// "root only".
+	GetThread(ctx context.Context, postID, rootID uint, maxDepth int) ([]*models.Comment, error)
+
+	// GetReplies returns parentID's direct children only, newest first.
+	GetReplies(ctx context.Context, parentID uint, offset, limit int) ([]*models.Comment, int64, error)
+
+	// CountReplies returns parentID's direct child count.
+	CountReplies(ctx context.Context, parentID uint) (int64, error)
+
+	// DeleteCascade deletes id along with every descendant found via its
+	// materialized Path prefix, so a subtree is never left with orphaned
+	// replies pointing at a deleted parent.
+	DeleteCascade(ctx context.Context, id uint) error
+
+	// GetPendingQueue returns comments awaiting moderation, oldest first.
+	GetPendingQueue(ctx context.Context, offset, limit int) ([]*models.Comment, int64, error)
+
+	// ListByIDs returns the comments matching ids, in no particular order.
+	ListByIDs(ctx context.Context, ids []uint) ([]*models.Comment, error)
+
+	// BulkUpdateStatus sets status on every comment in ids in a single
+	// statement, for admin queues processing a batch at once instead of one
+	// round-trip per comment.
+	BulkUpdateStatus(ctx context.Context, ids []uint, status string) error
+
+	// CountRecentPendingByAuthor counts authorID's comments still awaiting
+	// moderation that were created since since, used to trigger captcha
+	// gating on authors flooding the pending queue.
+	CountRecentPendingByAuthor(ctx context.Context, authorID uint, since time.Time) (int64, error)
 }
 
 type commentRepository struct {
@@ -30,17 +65,17 @@ func NewCommentRepository(db *gorm.DB) CommentRepository {
 	return &commentRepository{db: db}
 }
 
-func (r *commentRepository) Create(comment *models.Comment) error {
-	result := r.db.Create(comment)
+func (r *commentRepository) Create(ctx context.Context, comment *models.Comment) error {
+	result := r.db.WithContext(ctx).Create(comment)
 	if result.Error != nil {
 		return result.Error
 	}
 	return nil
 }
 
-func (r *commentRepository) GetByID(id uint) (*models.Comment, error) {
+func (r *commentRepository) GetByID(ctx context.Context, id uint) (*models.Comment, error) {
 	var comment models.Comment
-	result := r.db.Preload("Post").Preload("Author").First(&comment, id)
+	result := r.db.WithContext(ctx).Preload("Post").Preload("Author").First(&comment, id)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, ErrCommentNotFound
@@ -50,8 +85,8 @@ func (r *commentRepository) GetByID(id uint) (*models.Comment, error) {
 	return &comment, nil
 }
 
-func (r *commentRepository) Update(comment *models.Comment) error {
-	result := r.db.Save(comment)
+func (r *commentRepository) Update(ctx context.Context, comment *models.Comment) error {
+	result := r.db.WithContext(ctx).Save(comment)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -61,8 +96,8 @@ func (r *commentRepository) Update(comment *models.Comment) error {
 	return nil
 }
 
-func (r *commentRepository) Delete(id uint) error {
-	result := r.db.Delete(&models.Comment{}, id)
+func (r *commentRepository) Delete(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&models.Comment{}, id)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -72,11 +107,11 @@ func (r *commentRepository) Delete(id uint) error {
 	return nil
 }
 
-func (r *commentRepository) GetByPostID(postID uint, offset, limit int) ([]*models.Comment, int64, error) {
+func (r *commentRepository) GetByPostID(ctx context.Context, postID uint, offset, limit int) ([]*models.Comment, int64, error) {
 	var comments []*models.Comment
 	var total int64
 
-	query := r.db.Model(&models.Comment{}).Where("post_id = ?", postID)
+	query := r.db.WithContext(ctx).Model(&models.Comment{}).Where("post_id = ?", postID)
 
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
@@ -90,11 +125,11 @@ func (r *commentRepository) GetByPostID(postID uint, offset, limit int) ([]*mode
 	return comments, total, nil
 }
 
-func (r *commentRepository) GetByAuthorID(authorID uint, offset, limit int) ([]*models.Comment, int64, error) {
+func (r *commentRepository) GetByAuthorID(ctx context.Context, authorID uint, offset, limit int) ([]*models.Comment, int64, error) {
 	var comments []*models.Comment
 	var total int64
 
-	query := r.db.Model(&models.Comment{}).Where("author_id = ?", authorID)
+	query := r.db.WithContext(ctx).Model(&models.Comment{}).Where("author_id = ?", authorID)
 
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
@@ -108,11 +143,117 @@ func (r *commentRepository) GetByAuthorID(authorID uint, offset, limit int) ([]*
 	return comments, total, nil
 }
 
-func (r *commentRepository) GetByStatus(status string, offset, limit int) ([]*models.Comment, int64, error) {
+func (r *commentRepository) GetThread(ctx context.Context, postID, rootID uint, maxDepth int) ([]*models.Comment, error) {
+	var root models.Comment
+	if err := r.db.WithContext(ctx).Select("path", "depth").First(&root, rootID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, err
+	}
+
+	query := r.db.WithContext(ctx).
+		Where("post_id = ?", postID).
+		Where("path LIKE ?", root.Path+"%")
+
+	if maxDepth >= 0 {
+		query = query.Where("depth <= ?", root.Depth+maxDepth)
+	}
+
+	var comments []*models.Comment
+	if err := query.Preload("Author").Order("path ASC").Find(&comments).Error; err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+func (r *commentRepository) ListByIDs(ctx context.Context, ids []uint) ([]*models.Comment, error) {
+	var comments []*models.Comment
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&comments).Error; err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+func (r *commentRepository) BulkUpdateStatus(ctx context.Context, ids []uint, status string) error {
+	return r.db.WithContext(ctx).Model(&models.Comment{}).Where("id IN ?", ids).Update("status", status).Error
+}
+
+func (r *commentRepository) GetReplies(ctx context.Context, parentID uint, offset, limit int) ([]*models.Comment, int64, error) {
+	var comments []*models.Comment
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Comment{}).Where("parent_id = ?", parentID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	result := query.Preload("Author").Order("created_at DESC").Offset(offset).Limit(limit).Find(&comments)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	return comments, total, nil
+}
+
+func (r *commentRepository) CountReplies(ctx context.Context, parentID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Comment{}).Where("parent_id = ?", parentID).Count(&count).Error
+	return count, err
+}
+
+func (r *commentRepository) DeleteCascade(ctx context.Context, id uint) error {
+	var target models.Comment
+	if err := r.db.WithContext(ctx).Select("path").First(&target, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrCommentNotFound
+		}
+		return err
+	}
+
+	result := r.db.WithContext(ctx).Where("path LIKE ?", target.Path+"%").Delete(&models.Comment{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrCommentNotFound
+	}
+	return nil
+}
+
+func (r *commentRepository) GetPendingQueue(ctx context.Context, offset, limit int) ([]*models.Comment, int64, error) {
+	var comments []*models.Comment
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Comment{}).Where("status = ?", "pending")
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	result := query.Preload("Post").Preload("Author").Order("created_at ASC").Offset(offset).Limit(limit).Find(&comments)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	return comments, total, nil
+}
+
+func (r *commentRepository) CountRecentPendingByAuthor(ctx context.Context, authorID uint, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Comment{}).
+		Where("author_id = ? AND status = ? AND created_at >= ?", authorID, "pending", since).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *commentRepository) GetByStatus(ctx context.Context, status string, offset, limit int) ([]*models.Comment, int64, error) {
 	var comments []*models.Comment
 	var total int64
 
-	query := r.db.Model(&models.Comment{}).Where("status = ?", status)
+	query := r.db.WithContext(ctx).Model(&models.Comment{}).Where("status = ?", status)
 
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
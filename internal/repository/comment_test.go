@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cfrs2005/ppmtest/internal/models"
+	"github.com/cfrs2005/ppmtest/internal/testutil"
+)
+
+func TestCommentRepository_Create_ComputesPathAndDepth(t *testing.T) {
+	db := testutil.NewDB(t)
+	repo := NewCommentRepository(db)
+	ctx := context.Background()
+
+	root := &models.Comment{PostID: 1, AuthorID: 1, Content: "root"}
+	if err := repo.Create(ctx, root); err != nil {
+		t.Fatalf("Create(root) error = %v, want nil", err)
+	}
+
+	reply := &models.Comment{PostID: 1, AuthorID: 1, ParentID: &root.ID, Content: "reply"}
+	if err := repo.Create(ctx, reply); err != nil {
+		t.Fatalf("Create(reply) error = %v, want nil", err)
+	}
+
+	if reply.Depth != root.Depth+1 {
+		t.Errorf("reply.Depth = %d, want %d", reply.Depth, root.Depth+1)
+	}
+
+	updatedRoot, err := repo.GetByID(ctx, root.ID)
+	if err != nil {
+		t.Fatalf("GetByID(root) error = %v, want nil", err)
+	}
+	if updatedRoot.RepliesCount != 1 {
+		t.Errorf("root.RepliesCount = %d, want 1 (AfterCreate should bump the parent counter)", updatedRoot.RepliesCount)
+	}
+}
+
+func TestCommentRepository_DeleteCascade_RemovesSubtree(t *testing.T) {
+	db := testutil.NewDB(t)
+	repo := NewCommentRepository(db)
+	ctx := context.Background()
+
+	root := &models.Comment{PostID: 1, AuthorID: 1, Content: "root"}
+	if err := repo.Create(ctx, root); err != nil {
+		t.Fatalf("Create(root) error = %v, want nil", err)
+	}
+
+	child := &models.Comment{PostID: 1, AuthorID: 1, ParentID: &root.ID, Content: "child"}
+	if err := repo.Create(ctx, child); err != nil {
+		t.Fatalf("Create(child) error = %v, want nil", err)
+	}
+
+	grandchild := &models.Comment{PostID: 1, AuthorID: 1, ParentID: &child.ID, Content: "grandchild"}
+	if err := repo.Create(ctx, grandchild); err != nil {
+		t.Fatalf("Create(grandchild) error = %v, want nil", err)
+	}
+
+	if err := repo.DeleteCascade(ctx, root.ID); err != nil {
+		t.Fatalf("DeleteCascade(root) error = %v, want nil", err)
+	}
+
+	for _, id := range []uint{root.ID, child.ID, grandchild.ID} {
+		if _, err := repo.GetByID(ctx, id); err != ErrCommentNotFound {
+			t.Errorf("GetByID(%d) after DeleteCascade error = %v, want ErrCommentNotFound", id, err)
+		}
+	}
+}
+
+func TestCommentRepository_GetThread_OrdersByPath(t *testing.T) {
+	db := testutil.NewDB(t)
+	repo := NewCommentRepository(db)
+	ctx := context.Background()
+
+	root := &models.Comment{PostID: 1, AuthorID: 1, Content: "root"}
+	if err := repo.Create(ctx, root); err != nil {
+		t.Fatalf("Create(root) error = %v, want nil", err)
+	}
+	child := &models.Comment{PostID: 1, AuthorID: 1, ParentID: &root.ID, Content: "child"}
+	if err := repo.Create(ctx, child); err != nil {
+		t.Fatalf("Create(child) error = %v, want nil", err)
+	}
+
+	thread, err := repo.GetThread(ctx, 1, root.ID, -1)
+	if err != nil {
+		t.Fatalf("GetThread() error = %v, want nil", err)
+	}
+	if len(thread) != 2 {
+		t.Fatalf("GetThread() returned %d comments, want 2", len(thread))
+	}
+	if thread[0].ID != root.ID || thread[1].ID != child.ID {
+		t.Errorf("GetThread() order = [%d, %d], want [%d, %d] (parent before child)", thread[0].ID, thread[1].ID, root.ID, child.ID)
+	}
+}
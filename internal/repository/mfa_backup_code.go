@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cfrs2005/ppmtest/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var ErrBackupCodeNotFound = errors.New("backup code not found")
+
+// MFABackupCodeRepository stores the hashed single-use recovery codes
+// minted by UserService.ConfirmMFA.
+type MFABackupCodeRepository interface {
+	CreateBatch(ctx context.Context, codes []*models.MFABackupCode) error
+	ListUnusedByUserID(ctx context.Context, userID uint) ([]*models.MFABackupCode, error)
+	MarkUsed(ctx context.Context, id uint) error
+	DeleteAllByUserID(ctx context.Context, userID uint) error
+}
+
+type mfaBackupCodeRepository struct {
+	db *gorm.DB
+}
+
+func NewMFABackupCodeRepository(db *gorm.DB) MFABackupCodeRepository {
+	return &mfaBackupCodeRepository{db: db}
+}
+
+func (r *mfaBackupCodeRepository) CreateBatch(ctx context.Context, codes []*models.MFABackupCode) error {
+	if len(codes) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(&codes).Error
+}
+
+func (r *mfaBackupCodeRepository) ListUnusedByUserID(ctx context.Context, userID uint) ([]*models.MFABackupCode, error) {
+	var codes []*models.MFABackupCode
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Find(&codes).Error
+	return codes, err
+}
+
+func (r *mfaBackupCodeRepository) MarkUsed(ctx context.Context, id uint) error {
+	now := time.Now()
+	result := r.db.WithContext(ctx).Model(&models.MFABackupCode{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", now)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrBackupCodeNotFound
+	}
+	return nil
+}
+
+func (r *mfaBackupCodeRepository) DeleteAllByUserID(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&models.MFABackupCode{}).Error
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/cfrs2005/ppmtest/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type ModerationLogRepository interface {
+	Create(ctx context.Context, log *models.ModerationLog) error
+	ListByCommentID(ctx context.Context, commentID uint) ([]*models.ModerationLog, error)
+}
+
+type moderationLogRepository struct {
+	db *gorm.DB
+}
+
+func NewModerationLogRepository(db *gorm.DB) ModerationLogRepository {
+	return &moderationLogRepository{db: db}
+}
+
+func (r *moderationLogRepository) Create(ctx context.Context, log *models.ModerationLog) error {
+	return r.db.WithContext(ctx).Create(log).Error
+}
+
+func (r *moderationLogRepository) ListByCommentID(ctx context.Context, commentID uint) ([]*models.ModerationLog, error) {
+	var logs []*models.ModerationLog
+	err := r.db.WithContext(ctx).Where("comment_id = ?", commentID).Order("created_at ASC").Find(&logs).Error
+	if err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cfrs2005/ppmtest/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrOAuthClientNotFound       = errors.New("oauth client not found")
+	ErrOAuthClientAlreadyExists  = errors.New("oauth client already exists")
+	ErrAuthorizationCodeNotFound = errors.New("authorization code not found")
+)
+
+type OAuthClientRepository interface {
+	Create(ctx context.Context, client *models.OAuthClient) error
+	GetByClientID(ctx context.Context, clientID string) (*models.OAuthClient, error)
+	Update(ctx context.Context, client *models.OAuthClient) error
+	Delete(ctx context.Context, id uint) error
+	List(ctx context.Context, offset, limit int) ([]*models.OAuthClient, int64, error)
+}
+
+type oauthClientRepository struct {
+	db *gorm.DB
+}
+
+func NewOAuthClientRepository(db *gorm.DB) OAuthClientRepository {
+	return &oauthClientRepository{db: db}
+}
+
+func (r *oauthClientRepository) Create(ctx context.Context, client *models.OAuthClient) error {
+	result := r.db.WithContext(ctx).Create(client)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+			return ErrOAuthClientAlreadyExists
+		}
+		return result.Error
+	}
+	return nil
+}
+
+func (r *oauthClientRepository) GetByClientID(ctx context.Context, clientID string) (*models.OAuthClient, error) {
+	var client models.OAuthClient
+	result := r.db.WithContext(ctx).Where("client_id = ?", clientID).First(&client)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrOAuthClientNotFound
+		}
+		return nil, result.Error
+	}
+	return &client, nil
+}
+
+func (r *oauthClientRepository) Update(ctx context.Context, client *models.OAuthClient) error {
+	result := r.db.WithContext(ctx).Save(client)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrOAuthClientNotFound
+	}
+	return nil
+}
+
+func (r *oauthClientRepository) Delete(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&models.OAuthClient{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrOAuthClientNotFound
+	}
+	return nil
+}
+
+func (r *oauthClientRepository) List(ctx context.Context, offset, limit int) ([]*models.OAuthClient, int64, error) {
+	var clients []*models.OAuthClient
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.OAuthClient{})
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	result := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&clients)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	return clients, total, nil
+}
+
+// AuthorizationCodeRepository stores the short-lived codes minted by the
+// /oauth/authorize flow. Codes are single-use: Consume atomically fetches
+// and deletes a code so two concurrent redemptions can't both succeed.
+type AuthorizationCodeRepository interface {
+	Create(ctx context.Context, code *models.AuthorizationCode) error
+	Consume(ctx context.Context, code string) (*models.AuthorizationCode, error)
+}
+
+type authorizationCodeRepository struct {
+	db *gorm.DB
+}
+
+func NewAuthorizationCodeRepository(db *gorm.DB) AuthorizationCodeRepository {
+	return &authorizationCodeRepository{db: db}
+}
+
+func (r *authorizationCodeRepository) Create(ctx context.Context, code *models.AuthorizationCode) error {
+	result := r.db.WithContext(ctx).Create(code)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+func (r *authorizationCodeRepository) Consume(ctx context.Context, code string) (*models.AuthorizationCode, error) {
+	var authCode models.AuthorizationCode
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("code = ?", code).First(&authCode).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrAuthorizationCodeNotFound
+			}
+			return err
+		}
+		return tx.Delete(&authCode).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &authCode, nil
+}
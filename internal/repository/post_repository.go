@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"errors"
 
 	"github.com/cfrs2005/ppmtest/internal/models"
@@ -12,16 +13,27 @@ var (
 	ErrPostAlreadyExists = errors.New("post already exists")
 )
 
+// PostFilter narrows List/Search results to posts matching the given facets.
+// Zero-value fields are treated as "don't filter on this facet".
+type PostFilter struct {
+	Status   string
+	Tag      string // tag slug
+	Category string // category slug
+	Author   uint
+}
+
 // PostRepository defines the interface for post data operations
 type PostRepository interface {
-	Create(post *models.Post) error
-	FindByID(id uint) (*models.Post, error)
-	FindBySlug(slug string) (*models.Post, error)
-	Update(post *models.Post) error
-	Delete(id uint) error
-	List(offset, limit int, status string) ([]*models.Post, int64, error)
-	FindByAuthor(authorID uint, offset, limit int) ([]*models.Post, int64, error)
-	Search(query string, offset, limit int) ([]*models.Post, int64, error)
+	Create(ctx context.Context, post *models.Post) error
+	FindByID(ctx context.Context, id uint) (*models.Post, error)
+	FindBySlug(ctx context.Context, slug string) (*models.Post, error)
+	Update(ctx context.Context, post *models.Post) error
+	Delete(ctx context.Context, id uint) error
+	List(ctx context.Context, offset, limit int, filter PostFilter) ([]*models.Post, int64, error)
+	FindByAuthor(ctx context.Context, authorID uint, offset, limit int) ([]*models.Post, int64, error)
+	Search(ctx context.Context, query string, offset, limit int, filter PostFilter) ([]*models.Post, int64, error)
+	// SetTags replaces a post's tag associations with the given tags.
+	SetTags(ctx context.Context, postID uint, tags []*models.Tag) error
 }
 
 type postRepository struct {
@@ -33,8 +45,8 @@ func NewPostRepository(db *gorm.DB) PostRepository {
 	return &postRepository{db: db}
 }
 
-func (r *postRepository) Create(post *models.Post) error {
-	result := r.db.Create(post)
+func (r *postRepository) Create(ctx context.Context, post *models.Post) error {
+	result := r.db.WithContext(ctx).Create(post)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
 			return ErrPostAlreadyExists
@@ -44,9 +56,9 @@ func (r *postRepository) Create(post *models.Post) error {
 	return nil
 }
 
-func (r *postRepository) FindByID(id uint) (*models.Post, error) {
+func (r *postRepository) FindByID(ctx context.Context, id uint) (*models.Post, error) {
 	var post models.Post
-	result := r.db.Preload("Author").First(&post, id)
+	result := r.db.WithContext(ctx).Preload("Author").Preload("Category").Preload("Tags").First(&post, id)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, ErrPostNotFound
@@ -56,9 +68,9 @@ func (r *postRepository) FindByID(id uint) (*models.Post, error) {
 	return &post, nil
 }
 
-func (r *postRepository) FindBySlug(slug string) (*models.Post, error) {
+func (r *postRepository) FindBySlug(ctx context.Context, slug string) (*models.Post, error) {
 	var post models.Post
-	result := r.db.Preload("Author").Where("slug = ?", slug).First(&post)
+	result := r.db.WithContext(ctx).Preload("Author").Preload("Category").Preload("Tags").Where("slug = ?", slug).First(&post)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, ErrPostNotFound
@@ -68,8 +80,8 @@ func (r *postRepository) FindBySlug(slug string) (*models.Post, error) {
 	return &post, nil
 }
 
-func (r *postRepository) Update(post *models.Post) error {
-	result := r.db.Save(post)
+func (r *postRepository) Update(ctx context.Context, post *models.Post) error {
+	result := r.db.WithContext(ctx).Save(post)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -79,8 +91,8 @@ func (r *postRepository) Update(post *models.Post) error {
 	return nil
 }
 
-func (r *postRepository) Delete(id uint) error {
-	result := r.db.Delete(&models.Post{}, id)
+func (r *postRepository) Delete(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&models.Post{}, id)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -90,21 +102,18 @@ func (r *postRepository) Delete(id uint) error {
 	return nil
 }
 
-func (r *postRepository) List(offset, limit int, status string) ([]*models.Post, int64, error) {
+func (r *postRepository) List(ctx context.Context, offset, limit int, filter PostFilter) ([]*models.Post, int64, error) {
 	var posts []*models.Post
 	var total int64
 
-	query := r.db.Model(&models.Post{})
-	if status != "" && status != "all" {
-		query = query.Where("status = ?", status)
-	}
+	query := r.filtered(r.db.WithContext(ctx).Model(&models.Post{}), filter)
 
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	result := query.Preload("Author").
-		Order("created_at DESC").
+	result := query.Preload("Author").Preload("Category").Preload("Tags").
+		Order("posts.created_at DESC").
 		Offset(offset).
 		Limit(limit).
 		Find(&posts)
@@ -116,11 +125,36 @@ func (r *postRepository) List(offset, limit int, status string) ([]*models.Post,
 	return posts, total, nil
 }
 
-func (r *postRepository) FindByAuthor(authorID uint, offset, limit int) ([]*models.Post, int64, error) {
+// filtered applies the status/tag/category/author facets in PostFilter to
+// query, joining post_tags and categories only when needed.
+func (r *postRepository) filtered(query *gorm.DB, filter PostFilter) *gorm.DB {
+	if filter.Status != "" && filter.Status != "all" {
+		query = query.Where("posts.status = ?", filter.Status)
+	}
+
+	if filter.Author != 0 {
+		query = query.Where("posts.author_id = ?", filter.Author)
+	}
+
+	if filter.Category != "" {
+		query = query.Joins("JOIN categories ON categories.id = posts.category_id").
+			Where("categories.slug = ?", filter.Category)
+	}
+
+	if filter.Tag != "" {
+		query = query.Joins("JOIN post_tags ON post_tags.post_id = posts.id").
+			Joins("JOIN tags ON tags.id = post_tags.tag_id").
+			Where("tags.slug = ?", filter.Tag)
+	}
+
+	return query
+}
+
+func (r *postRepository) FindByAuthor(ctx context.Context, authorID uint, offset, limit int) ([]*models.Post, int64, error) {
 	var posts []*models.Post
 	var total int64
 
-	query := r.db.Model(&models.Post{}).Where("author_id = ?", authorID)
+	query := r.db.WithContext(ctx).Model(&models.Post{}).Where("author_id = ?", authorID)
 
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
@@ -138,12 +172,12 @@ func (r *postRepository) FindByAuthor(authorID uint, offset, limit int) ([]*mode
 	return posts, total, nil
 }
 
-func (r *postRepository) Search(query string, offset, limit int) ([]*models.Post, int64, error) {
+func (r *postRepository) Search(ctx context.Context, query string, offset, limit int, filter PostFilter) ([]*models.Post, int64, error) {
 	var posts []*models.Post
 	var total int64
 
-	searchQuery := r.db.Model(&models.Post{}).Where(
-		"title LIKE ? OR content LIKE ? OR summary LIKE ?",
+	searchQuery := r.filtered(r.db.WithContext(ctx).Model(&models.Post{}), filter).Where(
+		"posts.title LIKE ? OR posts.content LIKE ? OR posts.summary LIKE ?",
 		"%"+query+"%", "%"+query+"%", "%"+query+"%",
 	)
 
@@ -151,8 +185,8 @@ func (r *postRepository) Search(query string, offset, limit int) ([]*models.Post
 		return nil, 0, err
 	}
 
-	result := searchQuery.Preload("Author").
-		Order("created_at DESC").
+	result := searchQuery.Preload("Author").Preload("Category").Preload("Tags").
+		Order("posts.created_at DESC").
 		Offset(offset).
 		Limit(limit).
 		Find(&posts)
@@ -162,4 +196,10 @@ func (r *postRepository) Search(query string, offset, limit int) ([]*models.Post
 	}
 
 	return posts, total, nil
-}
\ No newline at end of file
+}
+
+// SetTags replaces the post's tag associations in a single transaction.
+func (r *postRepository) SetTags(ctx context.Context, postID uint, tags []*models.Tag) error {
+	post := &models.Post{ID: postID}
+	return r.db.WithContext(ctx).Model(post).Association("Tags").Replace(tags)
+}
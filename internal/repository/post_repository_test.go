@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cfrs2005/ppmtest/internal/models"
+
+	"github.com/cfrs2005/ppmtest/internal/testutil"
+)
+
+func TestPostRepository_Create_DuplicateSlug(t *testing.T) {
+	db := testutil.NewDB(t)
+	repo := NewPostRepository(db)
+	ctx := context.Background()
+
+	first := &models.Post{Title: "First", Slug: "dup-slug", Content: "c", AuthorID: 1}
+	if err := repo.Create(ctx, first); err != nil {
+		t.Fatalf("Create(first) error = %v, want nil", err)
+	}
+
+	dupe := &models.Post{Title: "Second", Slug: "dup-slug", Content: "c", AuthorID: 1}
+	err := repo.Create(ctx, dupe)
+	if err != ErrPostAlreadyExists {
+		t.Fatalf("Create(dupe slug) error = %v, want ErrPostAlreadyExists", err)
+	}
+}
+
+func TestPostRepository_FindByID_NotFound(t *testing.T) {
+	db := testutil.NewDB(t)
+	repo := NewPostRepository(db)
+
+	_, err := repo.FindByID(context.Background(), 999999)
+	if err != ErrPostNotFound {
+		t.Fatalf("FindByID(missing) error = %v, want ErrPostNotFound", err)
+	}
+}
+
+func TestPostRepository_FindBySlug(t *testing.T) {
+	db := testutil.NewDB(t)
+	repo := NewPostRepository(db)
+	ctx := context.Background()
+
+	post := &models.Post{Title: "Hello", Slug: "hello-world", Content: "c", AuthorID: 1}
+	if err := repo.Create(ctx, post); err != nil {
+		t.Fatalf("Create() error = %v, want nil", err)
+	}
+
+	found, err := repo.FindBySlug(ctx, "hello-world")
+	if err != nil {
+		t.Fatalf("FindBySlug() error = %v, want nil", err)
+	}
+	if found.ID != post.ID {
+		t.Errorf("FindBySlug() returned post %d, want %d", found.ID, post.ID)
+	}
+}
+
+func TestPostRepository_List_FiltersByStatus(t *testing.T) {
+	db := testutil.NewDB(t)
+	repo := NewPostRepository(db)
+	ctx := context.Background()
+
+	draft := &models.Post{Title: "Draft", Slug: "draft-post", Content: "c", AuthorID: 1, Status: "draft"}
+	published := &models.Post{Title: "Published", Slug: "published-post", Content: "c", AuthorID: 1, Status: "published"}
+	if err := repo.Create(ctx, draft); err != nil {
+		t.Fatalf("Create(draft) error = %v, want nil", err)
+	}
+	if err := repo.Create(ctx, published); err != nil {
+		t.Fatalf("Create(published) error = %v, want nil", err)
+	}
+
+	posts, total, err := repo.List(ctx, 0, 10, PostFilter{Status: "published"})
+	if err != nil {
+		t.Fatalf("List() error = %v, want nil", err)
+	}
+	if total != 1 {
+		t.Fatalf("List() total = %d, want 1", total)
+	}
+	if len(posts) != 1 || posts[0].ID != published.ID {
+		t.Errorf("List() = %v, want only published post %d", posts, published.ID)
+	}
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cfrs2005/ppmtest/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var ErrReportNotFound = errors.New("report not found")
+
+type ReportRepository interface {
+	Create(ctx context.Context, report *models.Report) error
+	GetByID(ctx context.Context, id uint) (*models.Report, error)
+	Update(ctx context.Context, report *models.Report) error
+	List(ctx context.Context, status string, offset, limit int) ([]*models.Report, int64, error)
+	GetOpenByReporterAndTarget(ctx context.Context, reporterID uint, targetType models.ReportTargetType, targetID uint) (*models.Report, error)
+	CountPendingByTarget(ctx context.Context, targetType models.ReportTargetType, targetID uint) (int64, error)
+}
+
+type reportRepository struct {
+	db *gorm.DB
+}
+
+func NewReportRepository(db *gorm.DB) ReportRepository {
+	return &reportRepository{db: db}
+}
+
+func (r *reportRepository) Create(ctx context.Context, report *models.Report) error {
+	return r.db.WithContext(ctx).Create(report).Error
+}
+
+func (r *reportRepository) GetByID(ctx context.Context, id uint) (*models.Report, error) {
+	var report models.Report
+	result := r.db.WithContext(ctx).First(&report, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrReportNotFound
+		}
+		return nil, result.Error
+	}
+	return &report, nil
+}
+
+func (r *reportRepository) Update(ctx context.Context, report *models.Report) error {
+	result := r.db.WithContext(ctx).Save(report)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrReportNotFound
+	}
+	return nil
+}
+
+func (r *reportRepository) List(ctx context.Context, status string, offset, limit int) ([]*models.Report, int64, error) {
+	var reports []*models.Report
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Report{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	result := query.Order("created_at ASC").Offset(offset).Limit(limit).Find(&reports)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	return reports, total, nil
+}
+
+func (r *reportRepository) GetOpenByReporterAndTarget(ctx context.Context, reporterID uint, targetType models.ReportTargetType, targetID uint) (*models.Report, error) {
+	var report models.Report
+	result := r.db.WithContext(ctx).
+		Where("reporter_id = ? AND target_type = ? AND target_id = ? AND status = ?", reporterID, targetType, targetID, models.ReportStatusPending).
+		First(&report)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrReportNotFound
+		}
+		return nil, result.Error
+	}
+	return &report, nil
+}
+
+func (r *reportRepository) CountPendingByTarget(ctx context.Context, targetType models.ReportTargetType, targetID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Report{}).
+		Where("target_type = ? AND target_id = ? AND status = ?", targetType, targetID, models.ReportStatusPending).
+		Count(&count).Error
+	return count, err
+}
@@ -1,24 +1,33 @@
 package repository
 
 import (
+	"context"
 	"errors"
+	"strings"
 
 	"github.com/cfrs2005/ppmtest/internal/models"
 	"gorm.io/gorm"
 )
 
 var (
-	ErrTagNotFound = errors.New("tag not found")
+	ErrTagNotFound      = errors.New("tag not found")
+	ErrTagAlreadyExists = errors.New("tag already exists")
 )
 
 // TagRepository defines the interface for tag data operations
 type TagRepository interface {
-	Create(tag *models.Tag) error
-	FindByID(id uint) (*models.Tag, error)
-	FindBySlug(slug string) (*models.Tag, error)
-	Update(tag *models.Tag) error
-	Delete(id uint) error
-	List(offset, limit int) ([]*models.Tag, int64, error)
+	Create(ctx context.Context, tag *models.Tag) error
+	FindByID(ctx context.Context, id uint) (*models.Tag, error)
+	FindBySlug(ctx context.Context, slug string) (*models.Tag, error)
+	Update(ctx context.Context, tag *models.Tag) error
+	Delete(ctx context.Context, id uint) error
+	List(ctx context.Context, offset, limit int) ([]*models.Tag, int64, error)
+	GetByName(ctx context.Context, name string) (*models.Tag, error)
+	// FindOrCreateByNames returns the tags matching names, creating any that
+	// don't exist yet so callers can attach a post to a consistent tag set.
+	FindOrCreateByNames(ctx context.Context, names []string) ([]*models.Tag, error)
+	// DeleteOrphaned removes tags that are no longer associated with any post.
+	DeleteOrphaned(ctx context.Context) error
 }
 
 type tagRepository struct {
@@ -30,14 +39,20 @@ func NewTagRepository(db *gorm.DB) TagRepository {
 	return &tagRepository{db: db}
 }
 
-func (r *tagRepository) Create(tag *models.Tag) error {
-	result := r.db.Create(tag)
-	return result.Error
+func (r *tagRepository) Create(ctx context.Context, tag *models.Tag) error {
+	result := r.db.WithContext(ctx).Create(tag)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+			return ErrTagAlreadyExists
+		}
+		return result.Error
+	}
+	return nil
 }
 
-func (r *tagRepository) FindByID(id uint) (*models.Tag, error) {
+func (r *tagRepository) FindByID(ctx context.Context, id uint) (*models.Tag, error) {
 	var tag models.Tag
-	result := r.db.First(&tag, id)
+	result := r.db.WithContext(ctx).First(&tag, id)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, ErrTagNotFound
@@ -47,9 +62,9 @@ func (r *tagRepository) FindByID(id uint) (*models.Tag, error) {
 	return &tag, nil
 }
 
-func (r *tagRepository) FindBySlug(slug string) (*models.Tag, error) {
+func (r *tagRepository) FindBySlug(ctx context.Context, slug string) (*models.Tag, error) {
 	var tag models.Tag
-	result := r.db.Where("slug = ?", slug).First(&tag)
+	result := r.db.WithContext(ctx).Where("slug = ?", slug).First(&tag)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, ErrTagNotFound
@@ -59,8 +74,8 @@ func (r *tagRepository) FindBySlug(slug string) (*models.Tag, error) {
 	return &tag, nil
 }
 
-func (r *tagRepository) Update(tag *models.Tag) error {
-	result := r.db.Save(tag)
+func (r *tagRepository) Update(ctx context.Context, tag *models.Tag) error {
+	result := r.db.WithContext(ctx).Save(tag)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -70,8 +85,8 @@ func (r *tagRepository) Update(tag *models.Tag) error {
 	return nil
 }
 
-func (r *tagRepository) Delete(id uint) error {
-	result := r.db.Delete(&models.Tag{}, id)
+func (r *tagRepository) Delete(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&models.Tag{}, id)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -81,18 +96,83 @@ func (r *tagRepository) Delete(id uint) error {
 	return nil
 }
 
-func (r *tagRepository) List(offset, limit int) ([]*models.Tag, int64, error) {
+func (r *tagRepository) List(ctx context.Context, offset, limit int) ([]*models.Tag, int64, error) {
 	var tags []*models.Tag
 	var total int64
 
-	if err := r.db.Model(&models.Tag{}).Count(&total).Error; err != nil {
+	if err := r.db.WithContext(ctx).Model(&models.Tag{}).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	result := r.db.Order("name ASC").Offset(offset).Limit(limit).Find(&tags)
+	result := r.db.WithContext(ctx).Order("name ASC").Offset(offset).Limit(limit).Find(&tags)
 	if result.Error != nil {
 		return nil, 0, result.Error
 	}
 
 	return tags, total, nil
-}
\ No newline at end of file
+}
+
+func (r *tagRepository) GetByName(ctx context.Context, name string) (*models.Tag, error) {
+	var tag models.Tag
+	result := r.db.WithContext(ctx).Where("name = ?", name).First(&tag)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrTagNotFound
+		}
+		return nil, result.Error
+	}
+	return &tag, nil
+}
+
+func (r *tagRepository) FindOrCreateByNames(ctx context.Context, names []string) ([]*models.Tag, error) {
+	tags := make([]*models.Tag, 0, len(names))
+
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		slug := slugify(name)
+
+		var tag models.Tag
+		result := r.db.WithContext(ctx).Where("slug = ?", slug).First(&tag)
+		if result.Error != nil {
+			if !errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return nil, result.Error
+			}
+			tag = models.Tag{Name: name, Slug: slug}
+			if err := r.db.WithContext(ctx).Create(&tag).Error; err != nil {
+				return nil, err
+			}
+		}
+
+		tags = append(tags, &tag)
+	}
+
+	return tags, nil
+}
+
+func (r *tagRepository) DeleteOrphaned(ctx context.Context) error {
+	return r.db.WithContext(ctx).Where("id NOT IN (?)", r.db.Table("post_tags").Select("tag_id")).Delete(&models.Tag{}).Error
+}
+
+func slugify(name string) string {
+	var b strings.Builder
+	lastDash := false
+
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z' || r >= '0' && r <= '9':
+			b.WriteRune(r)
+			lastDash = false
+		default:
+			if !lastDash {
+				b.WriteRune('-')
+				lastDash = true
+			}
+		}
+	}
+
+	return strings.Trim(b.String(), "-")
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// Repositories bundles every repository constructed against the same *gorm.DB,
+// so a UnitOfWork can hand a caller one bound to an open transaction.
+type Repositories struct {
+	Users          UserRepository
+	Posts          PostRepository
+	Comments       CommentRepository
+	Tags           TagRepository
+	Categories     CategoryRepository
+	Attachments    AttachmentRepository
+	OAuthClients   OAuthClientRepository
+	AuthCodes      AuthorizationCodeRepository
+	Captchas       CaptchaRepository
+	Reports        ReportRepository
+	ModerationLogs ModerationLogRepository
+}
+
+func newRepositories(db *gorm.DB) Repositories {
+	return Repositories{
+		Users:          NewUserRepository(db),
+		Posts:          NewPostRepository(db),
+		Comments:       NewCommentRepository(db),
+		Tags:           NewTagRepository(db),
+		Categories:     NewCategoryRepository(db),
+		Attachments:    NewAttachmentRepository(db),
+		OAuthClients:   NewOAuthClientRepository(db),
+		AuthCodes:      NewAuthorizationCodeRepository(db),
+		Captchas:       NewCaptchaRepository(db),
+		Reports:        NewReportRepository(db),
+		ModerationLogs: NewModerationLogRepository(db),
+	}
+}
+
+// UnitOfWork runs a group of repository operations atomically. Do opens a
+// gorm transaction, hands fn a Repositories bound to it, and commits or
+// rolls back as a single unit based on fn's return value.
+type UnitOfWork struct {
+	db *gorm.DB
+}
+
+func NewUnitOfWork(db *gorm.DB) *UnitOfWork {
+	return &UnitOfWork{db: db}
+}
+
+// Do runs fn inside a single database transaction. Any error returned by fn
+// rolls the transaction back; gorm.Transaction itself handles panics the
+// same way.
+func (u *UnitOfWork) Do(ctx context.Context, fn func(repos Repositories) error) error {
+	return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(newRepositories(tx))
+	})
+}
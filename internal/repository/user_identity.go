@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cfrs2005/ppmtest/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrIdentityNotFound      = errors.New("identity not found")
+	ErrIdentityAlreadyLinked = errors.New("identity is already linked to a user")
+)
+
+// UserIdentityRepository stores the external OIDC/OAuth2 accounts
+// (Google, GitHub, ...) linked to a local user.
+type UserIdentityRepository interface {
+	Create(ctx context.Context, identity *models.UserIdentity) error
+	GetByProvider(ctx context.Context, provider, providerUserID string) (*models.UserIdentity, error)
+	ListByUserID(ctx context.Context, userID uint) ([]*models.UserIdentity, error)
+	Delete(ctx context.Context, userID uint, provider string) error
+}
+
+type userIdentityRepository struct {
+	db *gorm.DB
+}
+
+func NewUserIdentityRepository(db *gorm.DB) UserIdentityRepository {
+	return &userIdentityRepository{db: db}
+}
+
+func (r *userIdentityRepository) Create(ctx context.Context, identity *models.UserIdentity) error {
+	result := r.db.WithContext(ctx).Create(identity)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+			return ErrIdentityAlreadyLinked
+		}
+		return result.Error
+	}
+	return nil
+}
+
+func (r *userIdentityRepository) GetByProvider(ctx context.Context, provider, providerUserID string) (*models.UserIdentity, error) {
+	var identity models.UserIdentity
+	result := r.db.WithContext(ctx).
+		Where("provider = ? AND provider_user_id = ?", provider, providerUserID).
+		First(&identity)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrIdentityNotFound
+		}
+		return nil, result.Error
+	}
+	return &identity, nil
+}
+
+func (r *userIdentityRepository) ListByUserID(ctx context.Context, userID uint) ([]*models.UserIdentity, error) {
+	var identities []*models.UserIdentity
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&identities).Error
+	return identities, err
+}
+
+func (r *userIdentityRepository) Delete(ctx context.Context, userID uint, provider string) error {
+	result := r.db.WithContext(ctx).
+		Where("user_id = ? AND provider = ?", userID, provider).
+		Delete(&models.UserIdentity{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrIdentityNotFound
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cfrs2005/ppmtest/internal/models"
+	"github.com/cfrs2005/ppmtest/internal/testutil"
+)
+
+func TestUserRepository_Create_DuplicateEmail(t *testing.T) {
+	db := testutil.NewDB(t)
+	repo := NewUserRepository(db)
+	ctx := context.Background()
+
+	first := &models.User{Username: "alice", Email: "alice@example.com", Password: "hash", Role: "author", Status: "active"}
+	if err := repo.Create(ctx, first); err != nil {
+		t.Fatalf("Create(first) error = %v, want nil", err)
+	}
+
+	dupe := &models.User{Username: "alice2", Email: "alice@example.com", Password: "hash", Role: "author", Status: "active"}
+	err := repo.Create(ctx, dupe)
+	if err != ErrUserAlreadyExists {
+		t.Fatalf("Create(dupe email) error = %v, want ErrUserAlreadyExists", err)
+	}
+}
+
+func TestUserRepository_Create_DuplicateUsername(t *testing.T) {
+	db := testutil.NewDB(t)
+	repo := NewUserRepository(db)
+	ctx := context.Background()
+
+	first := &models.User{Username: "bob", Email: "bob1@example.com", Password: "hash", Role: "author", Status: "active"}
+	if err := repo.Create(ctx, first); err != nil {
+		t.Fatalf("Create(first) error = %v, want nil", err)
+	}
+
+	dupe := &models.User{Username: "bob", Email: "bob2@example.com", Password: "hash", Role: "author", Status: "active"}
+	err := repo.Create(ctx, dupe)
+	if err != ErrUserAlreadyExists {
+		t.Fatalf("Create(dupe username) error = %v, want ErrUserAlreadyExists", err)
+	}
+}
+
+func TestUserRepository_GetByID_NotFound(t *testing.T) {
+	db := testutil.NewDB(t)
+	repo := NewUserRepository(db)
+
+	_, err := repo.GetByID(context.Background(), 999999)
+	if err != ErrUserNotFound {
+		t.Fatalf("GetByID(missing) error = %v, want ErrUserNotFound", err)
+	}
+}
+
+func TestUserRepository_GetByEmail(t *testing.T) {
+	db := testutil.NewDB(t)
+	repo := NewUserRepository(db)
+	ctx := context.Background()
+
+	user := &models.User{Username: "carol", Email: "carol@example.com", Password: "hash", Role: "author", Status: "active"}
+	if err := repo.Create(ctx, user); err != nil {
+		t.Fatalf("Create() error = %v, want nil", err)
+	}
+
+	found, err := repo.GetByEmail(ctx, "carol@example.com")
+	if err != nil {
+		t.Fatalf("GetByEmail() error = %v, want nil", err)
+	}
+	if found.ID != user.ID {
+		t.Errorf("GetByEmail() returned user %d, want %d", found.ID, user.ID)
+	}
+}
+
+func TestUserRepository_List_Pagination(t *testing.T) {
+	db := testutil.NewDB(t)
+	repo := NewUserRepository(db)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if _, err := testutil.SeedUser(db, i); err != nil {
+			t.Fatalf("SeedUser(%d) error = %v, want nil", i, err)
+		}
+	}
+
+	users, total, err := repo.List(ctx, 0, 2)
+	if err != nil {
+		t.Fatalf("List() error = %v, want nil", err)
+	}
+	if total != 3 {
+		t.Errorf("List() total = %d, want 3", total)
+	}
+	if len(users) != 2 {
+		t.Errorf("List() returned %d users, want 2", len(users))
+	}
+}
@@ -0,0 +1,28 @@
+package search
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cfrs2005/ppmtest/internal/repository"
+	"gorm.io/gorm"
+)
+
+// NewEngine selects an Engine implementation by name, as configured via
+// config.SearchConfig.Engine ("mysql" or "inverted"). "mysql" requires db's
+// FULLTEXT index to already exist (see EnsureFullTextIndex); "inverted"
+// builds its in-process index immediately from postRepo's current contents.
+func NewEngine(ctx context.Context, engine string, db *gorm.DB, postRepo repository.PostRepository) (Engine, error) {
+	switch engine {
+	case "", "mysql":
+		return NewMySQLEngine(db), nil
+	case "inverted":
+		e := NewInvertedIndexEngine(postRepo).(*invertedEngine)
+		if err := e.Build(ctx); err != nil {
+			return nil, fmt.Errorf("build inverted index: %w", err)
+		}
+		return e, nil
+	default:
+		return nil, fmt.Errorf("search: unknown engine %q", engine)
+	}
+}
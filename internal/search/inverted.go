@@ -0,0 +1,171 @@
+package search
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cfrs2005/ppmtest/internal/models"
+	"github.com/cfrs2005/ppmtest/internal/repository"
+)
+
+// invertedEngine is a simple in-process term-frequency index over posts,
+// used in place of mysqlEngine where a MySQL FULLTEXT index isn't available
+// (SQLite in dev, or unit tests with a mocked PostRepository).
+type invertedEngine struct {
+	postRepo repository.PostRepository
+
+	mu    sync.RWMutex
+	index map[string][]uint // term -> post IDs containing it
+	posts map[uint]*models.Post
+}
+
+// NewInvertedIndexEngine returns an Engine that builds and searches its
+// index entirely in process memory. Build must be called (and re-called
+// after posts change) before Search sees fresh data.
+func NewInvertedIndexEngine(postRepo repository.PostRepository) Engine {
+	return &invertedEngine{
+		postRepo: postRepo,
+		index:    make(map[string][]uint),
+		posts:    make(map[uint]*models.Post),
+	}
+}
+
+// Build re-reads every post from postRepo and rebuilds the index from
+// scratch. Cheap enough for SQLite/tests; not meant for production scale.
+func (e *invertedEngine) Build(ctx context.Context) error {
+	const pageSize = 500
+
+	index := make(map[string][]uint)
+	posts := make(map[uint]*models.Post)
+
+	for offset := 0; ; offset += pageSize {
+		batch, _, err := e.postRepo.List(ctx, offset, pageSize, repository.PostFilter{Status: "all"})
+		if err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			break
+		}
+
+		for _, post := range batch {
+			posts[post.ID] = post
+			for term := range tokenSet(post.Title + " " + post.Summary + " " + post.Content) {
+				index[term] = append(index[term], post.ID)
+			}
+		}
+
+		if len(batch) < pageSize {
+			break
+		}
+	}
+
+	e.mu.Lock()
+	e.index, e.posts = index, posts
+	e.mu.Unlock()
+
+	return nil
+}
+
+func (e *invertedEngine) Search(ctx context.Context, opts Options) ([]Hit, int64, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	terms := tokenize(opts.Query)
+	if len(terms) == 0 {
+		return nil, 0, nil
+	}
+
+	scores := make(map[uint]float64)
+	for _, term := range terms {
+		for _, postID := range e.index[term] {
+			scores[postID]++
+		}
+	}
+
+	if opts.Mode == ModePhrase {
+		phrase := strings.ToLower(strings.Join(terms, " "))
+		for postID := range scores {
+			post := e.posts[postID]
+			if !strings.Contains(strings.ToLower(post.Title+" "+post.Summary+" "+post.Content), phrase) {
+				delete(scores, postID)
+			}
+		}
+	}
+
+	var hits []Hit
+	for postID, score := range scores {
+		if opts.MinScore > 0 && score < opts.MinScore {
+			continue
+		}
+		post := e.posts[postID]
+		if !matchesFilter(post, opts.Filter) {
+			continue
+		}
+		hits = append(hits, Hit{
+			Post:    post,
+			Score:   score,
+			Snippet: snippet(post.Content, terms),
+		})
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Post.ID < hits[j].Post.ID
+	})
+
+	total := int64(len(hits))
+
+	start := opts.Offset
+	if start > len(hits) {
+		start = len(hits)
+	}
+	end := start + opts.Limit
+	if opts.Limit <= 0 || end > len(hits) {
+		end = len(hits)
+	}
+
+	return hits[start:end], total, nil
+}
+
+func matchesFilter(post *models.Post, filter repository.PostFilter) bool {
+	if filter.Status != "" && filter.Status != "all" && post.Status != filter.Status {
+		return false
+	}
+	if filter.Author != 0 && post.AuthorID != filter.Author {
+		return false
+	}
+	if filter.Category != "" && (post.Category == nil || post.Category.Slug != filter.Category) {
+		return false
+	}
+	if filter.Tag != "" {
+		found := false
+		for _, tag := range post.Tags {
+			if tag.Slug == filter.Tag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+}
+
+func tokenSet(text string) map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, term := range tokenize(text) {
+		set[term] = struct{}{}
+	}
+	return set
+}
@@ -0,0 +1,27 @@
+package search
+
+import "gorm.io/gorm"
+
+// EnsureFullTextIndex creates the FULLTEXT index post search relies on when
+// db is backed by MySQL. It is a no-op (and safe to call unconditionally
+// from database.Migrate) on any other driver, since SQLite/tests fall back
+// to the in-process inverted index instead.
+func EnsureFullTextIndex(db *gorm.DB) error {
+	if db.Dialector.Name() != "mysql" {
+		return nil
+	}
+
+	var count int64
+	if err := db.Raw(`
+		SELECT COUNT(*) FROM information_schema.statistics
+		WHERE table_schema = DATABASE() AND table_name = 'posts' AND index_name = 'ft_posts_search'
+	`).Scan(&count).Error; err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return nil
+	}
+
+	return db.Exec(`ALTER TABLE posts ADD FULLTEXT INDEX ft_posts_search (title, summary, content)`).Error
+}
@@ -0,0 +1,113 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cfrs2005/ppmtest/internal/models"
+	"github.com/cfrs2005/ppmtest/internal/repository"
+	"gorm.io/gorm"
+)
+
+// mysqlEngine searches posts via a MySQL FULLTEXT index on
+// posts(title, summary, content), ordering by MATCH ... AGAINST relevance.
+type mysqlEngine struct {
+	db *gorm.DB
+}
+
+// NewMySQLEngine returns an Engine backed by MySQL's native FULLTEXT index.
+// Callers must ensure the index exists, e.g. via EnsureFullTextIndex.
+func NewMySQLEngine(db *gorm.DB) Engine {
+	return &mysqlEngine{db: db}
+}
+
+type mysqlHitRow struct {
+	models.Post `gorm:"embedded"`
+	Score       float64
+}
+
+func (e *mysqlEngine) Search(ctx context.Context, opts Options) ([]Hit, int64, error) {
+	modeSQL, err := matchMode(opts.Mode)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	matchExpr := fmt.Sprintf("MATCH(title, summary, content) AGAINST (? %s)", modeSQL)
+
+	query := e.db.WithContext(ctx).Model(&models.Post{}).
+		Select(fmt.Sprintf("posts.*, %s AS score", matchExpr), opts.Query).
+		Where(matchExpr+" > 0", opts.Query)
+
+	query = applyPostFilter(query, opts.Filter)
+
+	if opts.MinScore > 0 {
+		query = query.Having("score >= ?", opts.MinScore)
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var rows []mysqlHitRow
+	result := query.Preload("Author").Preload("Category").Preload("Tags").
+		Order("score DESC").
+		Offset(opts.Offset).
+		Limit(opts.Limit).
+		Find(&rows)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	terms := strings.Fields(opts.Query)
+	hits := make([]Hit, 0, len(rows))
+	for i := range rows {
+		post := rows[i].Post
+		hits = append(hits, Hit{
+			Post:    &post,
+			Score:   rows[i].Score,
+			Snippet: snippet(post.Content, terms),
+		})
+	}
+
+	return hits, total, nil
+}
+
+func matchMode(mode Mode) (string, error) {
+	switch mode {
+	case "", ModeNatural:
+		return "IN NATURAL LANGUAGE MODE", nil
+	case ModePhrase:
+		return "IN BOOLEAN MODE", nil // caller is expected to quote the phrase in opts.Query
+	case ModeBoolean:
+		return "IN BOOLEAN MODE", nil
+	default:
+		return "", fmt.Errorf("search: unknown mode %q", mode)
+	}
+}
+
+// applyPostFilter mirrors postRepository.filtered so FULLTEXT search results
+// honor the same status/tag/category/author facets as PostRepository.List.
+func applyPostFilter(query *gorm.DB, filter repository.PostFilter) *gorm.DB {
+	if filter.Status != "" && filter.Status != "all" {
+		query = query.Where("posts.status = ?", filter.Status)
+	}
+
+	if filter.Author != 0 {
+		query = query.Where("posts.author_id = ?", filter.Author)
+	}
+
+	if filter.Category != "" {
+		query = query.Joins("JOIN categories ON categories.id = posts.category_id").
+			Where("categories.slug = ?", filter.Category)
+	}
+
+	if filter.Tag != "" {
+		query = query.Joins("JOIN post_tags ON post_tags.post_id = posts.id").
+			Joins("JOIN tags ON tags.id = post_tags.tag_id").
+			Where("tags.slug = ?", filter.Tag)
+	}
+
+	return query
+}
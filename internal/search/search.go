@@ -0,0 +1,50 @@
+// Package search abstracts full-text search over posts behind a single
+// Engine interface, so the query strategy (MySQL FULLTEXT indexes in
+// production, an in-process inverted index for SQLite/tests) can be swapped
+// via config without touching the service layer.
+package search
+
+import (
+	"context"
+
+	"github.com/cfrs2005/ppmtest/internal/models"
+	"github.com/cfrs2005/ppmtest/internal/repository"
+)
+
+// Mode selects how the query text is matched against indexed documents.
+type Mode string
+
+const (
+	// ModeNatural scores posts by how well they match the query terms
+	// overall (MySQL's NATURAL LANGUAGE MODE; term-frequency scoring for
+	// the in-process engine).
+	ModeNatural Mode = "natural"
+	// ModePhrase requires the query to appear as a contiguous phrase.
+	ModePhrase Mode = "phrase"
+	// ModeBoolean enables +/-/"" boolean operators (MySQL's BOOLEAN MODE;
+	// a best-effort equivalent for the in-process engine).
+	ModeBoolean Mode = "boolean"
+)
+
+// Options configures a single search request.
+type Options struct {
+	Query    string
+	Mode     Mode
+	MinScore float64
+	Filter   repository.PostFilter
+	Offset   int
+	Limit    int
+}
+
+// Hit is one search result: the matched post, its relevance score, and a
+// highlighted snippet of the content surrounding the match.
+type Hit struct {
+	Post    *models.Post
+	Score   float64
+	Snippet string
+}
+
+// Engine searches posts and reports relevance-scored hits.
+type Engine interface {
+	Search(ctx context.Context, opts Options) ([]Hit, int64, error)
+}
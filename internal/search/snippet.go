@@ -0,0 +1,52 @@
+package search
+
+import "strings"
+
+// snippetRadius is how many characters of context to keep on each side of
+// the first match when building a highlight snippet.
+const snippetRadius = 60
+
+// snippet returns a short excerpt of content centered on the first
+// case-insensitive occurrence of any term, wrapping the match in **bold**
+// markers. If no term is found, it falls back to the start of content.
+func snippet(content string, terms []string) string {
+	lower := strings.ToLower(content)
+
+	matchAt, matchLen := -1, 0
+	for _, term := range terms {
+		if term == "" {
+			continue
+		}
+		if idx := strings.Index(lower, strings.ToLower(term)); idx != -1 && (matchAt == -1 || idx < matchAt) {
+			matchAt, matchLen = idx, len(term)
+		}
+	}
+
+	if matchAt == -1 {
+		if len(content) <= 2*snippetRadius {
+			return content
+		}
+		return strings.TrimSpace(content[:2*snippetRadius]) + "..."
+	}
+
+	start := matchAt - snippetRadius
+	if start < 0 {
+		start = 0
+	}
+	end := matchAt + matchLen + snippetRadius
+	if end > len(content) {
+		end = len(content)
+	}
+
+	excerpt := content[start:matchAt] + "**" + content[matchAt:matchAt+matchLen] + "**" + content[matchAt+matchLen:end]
+
+	prefix, suffix := "", ""
+	if start > 0 {
+		prefix = "..."
+	}
+	if end < len(content) {
+		suffix = "..."
+	}
+
+	return prefix + strings.TrimSpace(excerpt) + suffix
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"image"
+	// Registered so image.DecodeConfig recognizes the formats posts
+	// commonly embed; the underlying bytes are never decoded further.
+	"fmt"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/cfrs2005/ppmtest/internal/models"
+	"github.com/cfrs2005/ppmtest/internal/repository"
+	"github.com/cfrs2005/ppmtest/internal/storage"
+)
+
+var (
+	ErrFileTooLarge  = errors.New("file exceeds the maximum size allowed for its type")
+	ErrQuotaExceeded = errors.New("upload would exceed your storage quota")
+	ErrNotOwner      = errors.New("attachment does not belong to this user")
+)
+
+// AttachmentService saves uploaded files through a pluggable storage.Storage
+// backend and records their metadata, enforcing the per-MIME-type size
+// limits and per-user quota from config.UploadConfig.
+type AttachmentService interface {
+	Upload(ctx context.Context, userID uint, fileHeader *multipart.FileHeader) (*models.Attachment, error)
+	GetByID(ctx context.Context, id uint) (*models.Attachment, error)
+	Delete(ctx context.Context, userID, id uint) error
+	// LinkToPost attaches ids to postID for every attachment userID owns,
+	// called from PostHandler.Create/Update's attachment_ids field.
+	LinkToPost(ctx context.Context, userID, postID uint, ids []uint) error
+}
+
+type attachmentService struct {
+	repo   repository.AttachmentRepository
+	store  storage.Storage
+	limits AttachmentLimits
+}
+
+// AttachmentLimits mirrors config.UploadConfig's size fields so the service
+// doesn't import internal/config directly (matching how commentService
+// takes maxDepth as a plain int rather than the whole CommentConfig).
+type AttachmentLimits struct {
+	MaxUserQuotaBytes int64
+	MaxImageBytes     int64
+	MaxVideoBytes     int64
+	MaxOtherBytes     int64
+}
+
+func NewAttachmentService(repo repository.AttachmentRepository, store storage.Storage, limits AttachmentLimits) AttachmentService {
+	return &attachmentService{repo: repo, store: store, limits: limits}
+}
+
+func classify(mimeType string) models.AttachmentType {
+	switch {
+	case strings.HasPrefix(mimeType, "image/"):
+		return models.AttachmentTypeImage
+	case strings.HasPrefix(mimeType, "video/"):
+		return models.AttachmentTypeVideo
+	default:
+		return models.AttachmentTypeOther
+	}
+}
+
+func (s *attachmentService) limitFor(t models.AttachmentType) int64 {
+	switch t {
+	case models.AttachmentTypeImage:
+		return s.limits.MaxImageBytes
+	case models.AttachmentTypeVideo:
+		return s.limits.MaxVideoBytes
+	default:
+		return s.limits.MaxOtherBytes
+	}
+}
+
+func newStorageKey(userID uint, originalName string) (string, error) {
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("users/%d/%s%s", userID, hex.EncodeToString(raw), filepath.Ext(originalName)), nil
+}
+
+func (s *attachmentService) Upload(ctx context.Context, userID uint, fileHeader *multipart.FileHeader) (*models.Attachment, error) {
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	sniff := make([]byte, 512)
+	n, err := io.ReadFull(file, sniff)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+	mimeType := http.DetectContentType(sniff[:n])
+	attachmentType := classify(mimeType)
+
+	size := fileHeader.Size
+	if limit := s.limitFor(attachmentType); size > limit {
+		return nil, ErrFileTooLarge
+	}
+
+	used, err := s.repo.SumSizeByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if used+size > s.limits.MaxUserQuotaBytes {
+		return nil, ErrQuotaExceeded
+	}
+
+	var width, height int
+	if attachmentType == models.AttachmentTypeImage {
+		if _, err := file.Seek(0, io.SeekStart); err == nil {
+			if cfg, _, err := image.DecodeConfig(file); err == nil {
+				width, height = cfg.Width, cfg.Height
+			}
+		}
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	key, err := newStorageKey(userID, fileHeader.Filename)
+	if err != nil {
+		return nil, err
+	}
+
+	hasher := sha256.New()
+	if err := s.store.Save(ctx, key, io.TeeReader(file, hasher), size); err != nil {
+		return nil, err
+	}
+
+	attachment := &models.Attachment{
+		UserID:      userID,
+		FileSize:    size,
+		MimeType:    mimeType,
+		Type:        attachmentType,
+		ImgWidth:    width,
+		ImgHeight:   height,
+		StoragePath: key,
+		Checksum:    hex.EncodeToString(hasher.Sum(nil)),
+	}
+
+	if err := s.repo.Create(ctx, attachment); err != nil {
+		_ = s.store.Delete(ctx, key)
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+func (s *attachmentService) GetByID(ctx context.Context, id uint) (*models.Attachment, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *attachmentService) Delete(ctx context.Context, userID, id uint) error {
+	attachment, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if attachment.UserID != userID {
+		return ErrNotOwner
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	return s.store.Delete(ctx, attachment.StoragePath)
+}
+
+func (s *attachmentService) LinkToPost(ctx context.Context, userID, postID uint, ids []uint) error {
+	return s.repo.LinkToPost(ctx, ids, postID, userID)
+}
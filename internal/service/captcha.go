@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/cfrs2005/ppmtest/internal/captcha"
+	"github.com/cfrs2005/ppmtest/internal/models"
+	"github.com/cfrs2005/ppmtest/internal/repository"
+)
+
+var (
+	ErrCaptchaInvalid     = errors.New("captcha code is incorrect")
+	ErrCaptchaExpired     = errors.New("captcha has expired")
+	ErrCaptchaAlreadyUsed = errors.New("captcha has already been used")
+	ErrCaptchaRequired    = errors.New("captcha verification is required")
+)
+
+const (
+	captchaCodeLength = 4
+	captchaTTL        = 5 * time.Minute
+)
+
+// CaptchaChallenge is what GET /captcha/new hands back to the client: Key
+// must be echoed alongside the solved Code on the gated endpoint, Image is
+// a data-URI-ready base64 PNG.
+type CaptchaChallenge struct {
+	Key         string
+	ImageBase64 string
+}
+
+// CaptchaService issues and verifies short-lived numeric image captchas,
+// gating registration and comment flood-protection (see commentService.Create).
+type CaptchaService interface {
+	New(ctx context.Context, ip string) (*CaptchaChallenge, error)
+	// Verify spends the captcha identified by key if code matches; a
+	// captcha can only ever be verified once.
+	Verify(ctx context.Context, key, code string) error
+}
+
+type captchaService struct {
+	repo repository.CaptchaRepository
+}
+
+func NewCaptchaService(repo repository.CaptchaRepository) CaptchaService {
+	return &captchaService{repo: repo}
+}
+
+func newCaptchaKey() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func (s *captchaService) New(ctx context.Context, ip string) (*CaptchaChallenge, error) {
+	code, err := captcha.NewCode(captchaCodeLength)
+	if err != nil {
+		return nil, err
+	}
+
+	image, err := captcha.Render(code)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := newCaptchaKey()
+	if err != nil {
+		return nil, err
+	}
+
+	record := &models.Captcha{
+		Key:       key,
+		IP:        ip,
+		Code:      code,
+		ExpiresAt: time.Now().Add(captchaTTL),
+	}
+	if err := s.repo.Create(ctx, record); err != nil {
+		return nil, err
+	}
+
+	return &CaptchaChallenge{
+		Key:         record.Key,
+		ImageBase64: base64.StdEncoding.EncodeToString(image),
+	}, nil
+}
+
+func (s *captchaService) Verify(ctx context.Context, key, code string) error {
+	record, err := s.repo.GetByKey(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	if record.UseTimes > 0 {
+		return ErrCaptchaAlreadyUsed
+	}
+	if time.Now().After(record.ExpiresAt) {
+		return ErrCaptchaExpired
+	}
+	if record.Code != code {
+		return ErrCaptchaInvalid
+	}
+
+	return s.repo.MarkUsed(ctx, record.ID)
+}
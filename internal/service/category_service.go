@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cfrs2005/ppmtest/internal/models"
+	"github.com/cfrs2005/ppmtest/internal/repository"
+)
+
+var (
+	ErrCategoryNameRequired = errors.New("category name is required")
+)
+
+// CategoryService defines the interface for category business logic
+type CategoryService interface {
+	CreateCategory(ctx context.Context, category *models.Category) error
+	GetCategoryByID(ctx context.Context, id uint) (*models.Category, error)
+	GetCategoryBySlug(ctx context.Context, slug string) (*models.Category, error)
+	UpdateCategory(ctx context.Context, category *models.Category) error
+	DeleteCategory(ctx context.Context, id uint) error
+	ListCategories(ctx context.Context, page, pageSize int) ([]*models.Category, int64, error)
+}
+
+type categoryService struct {
+	categoryRepo repository.CategoryRepository
+}
+
+// NewCategoryService creates a new category service
+func NewCategoryService(categoryRepo repository.CategoryRepository) CategoryService {
+	return &categoryService{categoryRepo: categoryRepo}
+}
+
+func (s *categoryService) CreateCategory(ctx context.Context, category *models.Category) error {
+	if category.Name == "" {
+		return ErrCategoryNameRequired
+	}
+
+	if category.Slug == "" {
+		category.Slug = generateSlug(category.Name)
+	}
+
+	return s.categoryRepo.Create(ctx, category)
+}
+
+func (s *categoryService) GetCategoryByID(ctx context.Context, id uint) (*models.Category, error) {
+	return s.categoryRepo.FindByID(ctx, id)
+}
+
+func (s *categoryService) GetCategoryBySlug(ctx context.Context, slug string) (*models.Category, error) {
+	return s.categoryRepo.FindBySlug(ctx, slug)
+}
+
+func (s *categoryService) UpdateCategory(ctx context.Context, category *models.Category) error {
+	if category.Name == "" {
+		return ErrCategoryNameRequired
+	}
+
+	return s.categoryRepo.Update(ctx, category)
+}
+
+func (s *categoryService) DeleteCategory(ctx context.Context, id uint) error {
+	return s.categoryRepo.Delete(ctx, id)
+}
+
+func (s *categoryService) ListCategories(ctx context.Context, page, pageSize int) ([]*models.Category, int64, error) {
+	offset := (page - 1) * pageSize
+	return s.categoryRepo.List(ctx, offset, pageSize)
+}
@@ -1,52 +1,200 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"time"
 
-	"ppmtest/internal/models"
-	"ppmtest/internal/repository"
+	"github.com/cfrs2005/ppmtest/internal/models"
+	"github.com/cfrs2005/ppmtest/internal/repository"
+
+	"github.com/cfrs2005/ppmtest/internal/metrics"
 )
 
 var (
-	ErrUnauthorizedComment = errors.New("unauthorized to modify this comment")
+	ErrUnauthorizedComment  = errors.New("unauthorized to modify this comment")
+	ErrMaxDepthExceeded     = errors.New("comment reply depth exceeds the maximum allowed")
+	ErrCommentPostMismatch  = errors.New("reply must target a comment on the same post")
+	ErrInvalidCommentStatus = errors.New("invalid comment status")
+)
+
+// validCommentStatuses are the only values BulkSetStatus will accept,
+// matching what Approve/Reject/MarkAsSpam already set individually.
+var validCommentStatuses = map[string]bool{
+	"pending":  true,
+	"approved": true,
+	"spam":     true,
+}
+
+// defaultMaxCommentDepth bounds how deeply comments may nest when a service
+// isn't given an explicit limit via NewCommentService.
+const defaultMaxCommentDepth = 10
+
+// defaultPendingFloodThreshold and pendingFloodWindow bound how many
+// pending (unmoderated) comments an author may accumulate in the window
+// before Create starts requiring a solved captcha. This tree has no
+// anonymous comment-submission path (every comment route sits behind
+// AuthMiddleware, and Comment.AuthorID is a required field), so the gate
+// targets authenticated accounts flooding the moderation queue rather than
+// anonymous posters.
+const (
+	defaultPendingFloodThreshold = 5
+	pendingFloodWindow           = time.Hour
 )
 
+// defaultSpamConfidenceThreshold is the GLM spam-verdict confidence above
+// which Create auto-sets a new comment's status to "spam" instead of
+// "pending", used when NewCommentService isn't given an explicit threshold.
+const defaultSpamConfidenceThreshold = 0.7
+
 type CommentService interface {
-	Create(comment *models.Comment, authorID uint) error
-	GetByID(id uint) (*models.Comment, error)
-	Update(comment *models.Comment, userID uint) error
-	Delete(id uint, userID uint) error
-	GetByPostID(postID uint, page, pageSize int) ([]*models.Comment, int64, error)
-	GetByAuthorID(authorID uint, page, pageSize int) ([]*models.Comment, int64, error)
-	GetByStatus(status string, page, pageSize int) ([]*models.Comment, int64, error)
-	Approve(id uint) error
-	Reject(id uint) error
-	MarkAsSpam(id uint) error
+	Create(ctx context.Context, comment *models.Comment, authorID uint, captchaKey, captchaCode string) error
+	GetByID(ctx context.Context, id uint) (*models.Comment, error)
+	Update(ctx context.Context, comment *models.Comment, userID uint) error
+	Delete(ctx context.Context, id uint, userID uint) error
+	GetByPostID(ctx context.Context, postID uint, page, pageSize int) ([]*models.Comment, int64, error)
+	GetByAuthorID(ctx context.Context, authorID uint, page, pageSize int) ([]*models.Comment, int64, error)
+	GetByStatus(ctx context.Context, status string, page, pageSize int) ([]*models.Comment, int64, error)
+	GetThread(ctx context.Context, postID, rootID uint, maxDepth int) ([]*models.Comment, error)
+	GetReplies(ctx context.Context, parentID uint, page, pageSize int) ([]*models.Comment, int64, error)
+	CountReplies(ctx context.Context, parentID uint) (int64, error)
+	GetPendingQueue(ctx context.Context, page, pageSize int) ([]*models.Comment, int64, error)
+	Approve(ctx context.Context, id uint) error
+	Reject(ctx context.Context, id uint) error
+	MarkAsSpam(ctx context.Context, id uint) error
+
+	// BulkSetStatus moves every comment in ids to status in a single
+	// transaction, recording a ModerationLog entry per comment so admin
+	// queues can process a batch without one round-trip per comment.
+	BulkSetStatus(ctx context.Context, ids []uint, status string, moderatorID uint, reason string) error
+
+	// ListModerationHistory returns commentID's audit trail, oldest first.
+	ListModerationHistory(ctx context.Context, commentID uint) ([]*models.ModerationLog, error)
 }
 
 type commentService struct {
-	commentRepo repository.CommentRepository
+	commentRepo             repository.CommentRepository
+	moderationLogRepo       repository.ModerationLogRepository
+	maxDepth                int
+	captchaService          CaptchaService
+	floodThreshold          int
+	uow                     *repository.UnitOfWork
+	glmService              GLMService
+	spamConfidenceThreshold float64
 }
 
-func NewCommentService(commentRepo repository.CommentRepository) CommentService {
+// NewCommentService wires commentRepo and maxDepth as before. captchaService
+// may be nil, in which case the flood-protection captcha gate in Create is
+// skipped entirely (e.g. for tests that don't care about it). uow may also
+// be nil, in which case Create falls back to commentRepo alone and skips
+// the post/author existence checks that require the transaction-bound
+// Repositories a UnitOfWork hands out; BulkSetStatus falls back the same
+// way, using moderationLogRepo directly instead of repos.ModerationLogs.
+// glmService may also be nil (main.go leaves it nil when GLM.APIKey isn't
+// configured), in which case Create skips spam screening entirely.
+// spamConfidenceThreshold <= 0 falls back to defaultSpamConfidenceThreshold.
+func NewCommentService(commentRepo repository.CommentRepository, moderationLogRepo repository.ModerationLogRepository, maxDepth int, captchaService CaptchaService, uow *repository.UnitOfWork, glmService GLMService, spamConfidenceThreshold float64) CommentService {
+	if maxDepth <= 0 {
+		maxDepth = defaultMaxCommentDepth
+	}
+	if spamConfidenceThreshold <= 0 {
+		spamConfidenceThreshold = defaultSpamConfidenceThreshold
+	}
 	return &commentService{
-		commentRepo: commentRepo,
+		commentRepo:             commentRepo,
+		moderationLogRepo:       moderationLogRepo,
+		maxDepth:                maxDepth,
+		captchaService:          captchaService,
+		floodThreshold:          defaultPendingFloodThreshold,
+		uow:                     uow,
+		glmService:              glmService,
+		spamConfidenceThreshold: spamConfidenceThreshold,
 	}
 }
 
-func (s *commentService) Create(comment *models.Comment, authorID uint) error {
+func (s *commentService) Create(ctx context.Context, comment *models.Comment, authorID uint, captchaKey, captchaCode string) error {
 	comment.AuthorID = authorID
 	comment.Status = "pending"
 
-	return s.commentRepo.Create(comment)
+	if s.glmService != nil {
+		if verdict, err := s.glmService.DetectSpamComment(comment.Content); err == nil {
+			if verdict.IsSpam && verdict.Confidence > s.spamConfidenceThreshold {
+				comment.Status = "spam"
+			}
+		}
+	}
+
+	if s.captchaService != nil {
+		pending, err := s.commentRepo.CountRecentPendingByAuthor(ctx, authorID, time.Now().Add(-pendingFloodWindow))
+		if err != nil {
+			return err
+		}
+		if pending >= int64(s.floodThreshold) {
+			if captchaKey == "" || captchaCode == "" {
+				return ErrCaptchaRequired
+			}
+			if err := s.captchaService.Verify(ctx, captchaKey, captchaCode); err != nil {
+				return err
+			}
+		}
+	}
+
+	var err error
+	if s.uow == nil {
+		err = s.createComment(ctx, s.commentRepo, nil, nil, comment)
+	} else {
+		err = s.uow.Do(ctx, func(repos repository.Repositories) error {
+			return s.createComment(ctx, repos.Comments, repos.Posts, repos.Users, comment)
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	metrics.CommentsCreatedTotal.Inc(comment.Status)
+	return nil
 }
 
-func (s *commentService) GetByID(id uint) (*models.Comment, error) {
-	return s.commentRepo.GetByID(id)
+// createComment validates comment against postRepo/userRepo (when given)
+// and its parent's depth, then writes it via commentRepo. Called either
+// directly (s.uow == nil) or from inside a single UnitOfWork transaction,
+// so the post/author reads and the comment write commit or roll back
+// together.
+func (s *commentService) createComment(ctx context.Context, commentRepo repository.CommentRepository, postRepo repository.PostRepository, userRepo repository.UserRepository, comment *models.Comment) error {
+	if postRepo != nil {
+		if _, err := postRepo.FindByID(ctx, comment.PostID); err != nil {
+			return err
+		}
+	}
+
+	if userRepo != nil {
+		if _, err := userRepo.GetByID(ctx, comment.AuthorID); err != nil {
+			return err
+		}
+	}
+
+	if comment.ParentID != nil {
+		parent, err := commentRepo.GetByID(ctx, *comment.ParentID)
+		if err != nil {
+			return err
+		}
+		if parent.PostID != comment.PostID {
+			return ErrCommentPostMismatch
+		}
+		if parent.Depth+1 > s.maxDepth {
+			return ErrMaxDepthExceeded
+		}
+	}
+
+	return commentRepo.Create(ctx, comment)
 }
 
-func (s *commentService) Update(comment *models.Comment, userID uint) error {
-	existingComment, err := s.commentRepo.GetByID(comment.ID)
+func (s *commentService) GetByID(ctx context.Context, id uint) (*models.Comment, error) {
+	return s.commentRepo.GetByID(ctx, id)
+}
+
+func (s *commentService) Update(ctx context.Context, comment *models.Comment, userID uint) error {
+	existingComment, err := s.commentRepo.GetByID(ctx, comment.ID)
 	if err != nil {
 		return err
 	}
@@ -55,11 +203,14 @@ func (s *commentService) Update(comment *models.Comment, userID uint) error {
 		return ErrUnauthorizedComment
 	}
 
-	return s.commentRepo.Update(comment)
+	return s.commentRepo.Update(ctx, comment)
 }
 
-func (s *commentService) Delete(id uint, userID uint) error {
-	comment, err := s.commentRepo.GetByID(id)
+// Delete removes comment and every descendant reply beneath it (via
+// commentRepo.DeleteCascade), so deleting a comment never leaves orphaned
+// replies pointing at a parent that no longer exists.
+func (s *commentService) Delete(ctx context.Context, id uint, userID uint) error {
+	comment, err := s.commentRepo.GetByID(ctx, id)
 	if err != nil {
 		return err
 	}
@@ -68,50 +219,122 @@ func (s *commentService) Delete(id uint, userID uint) error {
 		return ErrUnauthorizedComment
 	}
 
-	return s.commentRepo.Delete(id)
+	return s.commentRepo.DeleteCascade(ctx, id)
 }
 
-func (s *commentService) GetByPostID(postID uint, page, pageSize int) ([]*models.Comment, int64, error) {
+func (s *commentService) GetByPostID(ctx context.Context, postID uint, page, pageSize int) ([]*models.Comment, int64, error) {
 	offset := (page - 1) * pageSize
-	return s.commentRepo.GetByPostID(postID, offset, pageSize)
+	return s.commentRepo.GetByPostID(ctx, postID, offset, pageSize)
 }
 
-func (s *commentService) GetByAuthorID(authorID uint, page, pageSize int) ([]*models.Comment, int64, error) {
+func (s *commentService) GetByAuthorID(ctx context.Context, authorID uint, page, pageSize int) ([]*models.Comment, int64, error) {
 	offset := (page - 1) * pageSize
-	return s.commentRepo.GetByAuthorID(authorID, offset, pageSize)
+	return s.commentRepo.GetByAuthorID(ctx, authorID, offset, pageSize)
 }
 
-func (s *commentService) GetByStatus(status string, page, pageSize int) ([]*models.Comment, int64, error) {
+func (s *commentService) GetByStatus(ctx context.Context, status string, page, pageSize int) ([]*models.Comment, int64, error) {
 	offset := (page - 1) * pageSize
-	return s.commentRepo.GetByStatus(status, offset, pageSize)
+	return s.commentRepo.GetByStatus(ctx, status, offset, pageSize)
 }
 
-func (s *commentService) Approve(id uint) error {
-	comment, err := s.commentRepo.GetByID(id)
+func (s *commentService) GetThread(ctx context.Context, postID, rootID uint, maxDepth int) ([]*models.Comment, error) {
+	if maxDepth < 0 || maxDepth > s.maxDepth {
+		maxDepth = s.maxDepth
+	}
+	return s.commentRepo.GetThread(ctx, postID, rootID, maxDepth)
+}
+
+func (s *commentService) GetReplies(ctx context.Context, parentID uint, page, pageSize int) ([]*models.Comment, int64, error) {
+	offset := (page - 1) * pageSize
+	return s.commentRepo.GetReplies(ctx, parentID, offset, pageSize)
+}
+
+func (s *commentService) CountReplies(ctx context.Context, parentID uint) (int64, error) {
+	return s.commentRepo.CountReplies(ctx, parentID)
+}
+
+func (s *commentService) GetPendingQueue(ctx context.Context, page, pageSize int) ([]*models.Comment, int64, error) {
+	offset := (page - 1) * pageSize
+	return s.commentRepo.GetPendingQueue(ctx, offset, pageSize)
+}
+
+func (s *commentService) Approve(ctx context.Context, id uint) error {
+	comment, err := s.commentRepo.GetByID(ctx, id)
 	if err != nil {
 		return err
 	}
 
 	comment.Status = "approved"
-	return s.commentRepo.Update(comment)
+	return s.commentRepo.Update(ctx, comment)
 }
 
-func (s *commentService) Reject(id uint) error {
-	comment, err := s.commentRepo.GetByID(id)
+func (s *commentService) Reject(ctx context.Context, id uint) error {
+	comment, err := s.commentRepo.GetByID(ctx, id)
 	if err != nil {
 		return err
 	}
 
 	comment.Status = "pending"
-	return s.commentRepo.Update(comment)
+	return s.commentRepo.Update(ctx, comment)
+}
+
+// BulkSetStatus moves every comment in ids to status, writing one
+// ModerationLog entry per comment recording its prior status. Runs inside a
+// single transaction when s.uow is configured, so the status change and its
+// audit trail commit or roll back together.
+func (s *commentService) BulkSetStatus(ctx context.Context, ids []uint, status string, moderatorID uint, reason string) error {
+	if !validCommentStatuses[status] {
+		return ErrInvalidCommentStatus
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if s.uow == nil {
+		return s.bulkSetStatus(ctx, s.commentRepo, s.moderationLogRepo, ids, status, moderatorID, reason)
+	}
+
+	return s.uow.Do(ctx, func(repos repository.Repositories) error {
+		return s.bulkSetStatus(ctx, repos.Comments, repos.ModerationLogs, ids, status, moderatorID, reason)
+	})
+}
+
+func (s *commentService) bulkSetStatus(ctx context.Context, commentRepo repository.CommentRepository, moderationLogRepo repository.ModerationLogRepository, ids []uint, status string, moderatorID uint, reason string) error {
+	comments, err := commentRepo.ListByIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	if err := commentRepo.BulkUpdateStatus(ctx, ids, status); err != nil {
+		return err
+	}
+
+	for _, comment := range comments {
+		log := &models.ModerationLog{
+			CommentID:   comment.ID,
+			ModeratorID: moderatorID,
+			FromStatus:  comment.Status,
+			ToStatus:    status,
+			Reason:      reason,
+		}
+		if err := moderationLogRepo.Create(ctx, log); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *commentService) ListModerationHistory(ctx context.Context, commentID uint) ([]*models.ModerationLog, error) {
+	return s.moderationLogRepo.ListByCommentID(ctx, commentID)
 }
 
-func (s *commentService) MarkAsSpam(id uint) error {
-	comment, err := s.commentRepo.GetByID(id)
+func (s *commentService) MarkAsSpam(ctx context.Context, id uint) error {
+	comment, err := s.commentRepo.GetByID(ctx, id)
 	if err != nil {
 		return err
 	}
 
 	comment.Status = "spam"
-	return s.commentRepo.Update(comment)
+	return s.commentRepo.Update(ctx, comment)
 }
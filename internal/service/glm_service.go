@@ -4,17 +4,22 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/cfrs2005/ppmtest/internal/ratelimit"
+
 	"github.com/cfrs2005/ppmtest/internal/glm"
 )
 
 type GLMService interface {
 	GenerateSummary(content string, maxLength int) (string, error)
 	GenerateTags(content string, count int) ([]string, error)
-	DetectSpamComment(content string) (bool, error)
+	DetectSpamComment(content string) (*glm.SpamVerdict, error)
 	GeneratePostContent(topic string) (string, error)
+	GeneratePostContentStream(topic string, onChunk func(delta string) error) error
 }
 
 type glmService struct {
@@ -53,17 +58,17 @@ func (s *glmService) GenerateTags(content string, count int) ([]string, error) {
 	return tags, nil
 }
 
-func (s *glmService) DetectSpamComment(content string) (bool, error) {
+func (s *glmService) DetectSpamComment(content string) (*glm.SpamVerdict, error) {
 	if content == "" {
-		return false, fmt.Errorf("content cannot be empty")
+		return nil, fmt.Errorf("content cannot be empty")
 	}
 
-	isSpam, err := s.client.DetectSpam(content)
+	verdict, err := s.client.DetectSpam(content)
 	if err != nil {
-		return false, fmt.Errorf("failed to detect spam: %w", err)
+		return nil, fmt.Errorf("failed to detect spam: %w", err)
 	}
 
-	return isSpam, nil
+	return verdict, nil
 }
 
 func (s *glmService) GeneratePostContent(topic string) (string, error) {
@@ -81,19 +86,97 @@ func (s *glmService) GeneratePostContent(topic string) (string, error) {
 	return content, nil
 }
 
+// GeneratePostContentStream is GeneratePostContent with incremental delivery:
+// onChunk is invoked as each fragment of the post arrives instead of waiting
+// for the full 500-1000 character completion to buffer.
+func (s *glmService) GeneratePostContentStream(topic string, onChunk func(delta string) error) error {
+	if topic == "" {
+		return fmt.Errorf("topic cannot be empty")
+	}
+
+	prompt := fmt.Sprintf("请写一篇关于\"%s\"的技术博客，要求：\n1. 内容专业且易懂\n2. 包含代码示例\n3. 字数在500-1000字之间", topic)
+
+	if err := s.client.GenerateContentStream(prompt, onChunk); err != nil {
+		return fmt.Errorf("failed to generate post content: %w", err)
+	}
+
+	return nil
+}
+
+// Priority buckets GLMTask dispatch order. Workers drain highChan more
+// often than normalChan, and normalChan more often than lowChan, via a
+// weighted round robin in worker, but never starve a lower queue entirely.
+type Priority int
+
+const (
+	PriorityLow Priority = iota
+	PriorityNormal
+	PriorityHigh
+)
+
+// taskPriority is the default Priority for each Submit* convenience method:
+// spam checks are fast and block a user-facing comment submission, so they
+// go in highChan; tags generation is a background enrichment step (normal);
+// summaries are the slowest and least time-sensitive, so they go in lowChan.
+var taskPriority = map[string]Priority{
+	"spam":    PriorityHigh,
+	"tags":    PriorityNormal,
+	"summary": PriorityLow,
+}
+
+// workerSchedule is the weighted round-robin cycle workers step through:
+// 4 high-priority turns for every 2 normal and 1 low, so high-priority work
+// is preferred without low-priority tasks starving indefinitely.
+var workerSchedule = []Priority{
+	PriorityHigh, PriorityHigh, PriorityHigh, PriorityHigh,
+	PriorityNormal, PriorityNormal,
+	PriorityLow,
+}
+
+// queueDepth is how many tasks each priority channel buffers before Submit*
+// blocks (or SubmitWithContext's ctx is checked).
+const queueDepth = 100
+
+// tenantThrottleRetry is how long a throttled task waits before being
+// re-enqueued at its original priority, when a tenant has exceeded its
+// per-tenant token bucket share.
+const tenantThrottleRetry = 50 * time.Millisecond
+
+// latencyRingSize bounds how many recent per-task-type latency samples
+// Metrics' p50/p95 are computed from.
+const latencyRingSize = 256
+
 type AsyncGLMService struct {
-	client   *glm.Client
-	taskChan chan GLMTask
-	wg       sync.WaitGroup
-	ctx      context.Context
-	cancel   context.CancelFunc
+	client *glm.Client
+
+	highChan   chan GLMTask
+	normalChan chan GLMTask
+	lowChan    chan GLMTask
+
+	tenantLimiter *ratelimit.Limiter
+	inFlight      int32
+	latencies     map[string]*latencyRing
+
+	wg     sync.WaitGroup
+	ctx    context.Context
+	cancel context.CancelFunc
 }
 
+// GLMTask is one unit of async GLM work. TenantID is optional: when set and
+// the service was constructed with per-tenant rate limiting, tasks from a
+// tenant that has exceeded its share are deferred (not dropped) until
+// capacity frees up, so one noisy tenant can't starve the others.
 type GLMTask struct {
-	ID     string
-	Type   string
-	Input  interface{}
-	Result chan<- TaskResult
+	ID       string
+	Type     string
+	Priority Priority
+	TenantID string
+	Input    interface{}
+	Result   chan<- TaskResult
+
+	// ctx is set by SubmitWithContext; a nil ctx (the Submit* wrappers'
+	// default) means the task can't be canceled once enqueued.
+	ctx context.Context
 }
 
 type TaskResult struct {
@@ -102,14 +185,31 @@ type TaskResult struct {
 	Error error
 }
 
-func NewAsyncGLMService(client *glm.Client, workers int) *AsyncGLMService {
+// NewAsyncGLMService starts workers goroutines draining a three-tier
+// priority queue (high/normal/low, each buffered to queueDepth). tenantRPS
+// and tenantBurst configure the per-tenant token bucket GLMTask.TenantID is
+// checked against; tenantRPS <= 0 disables per-tenant throttling entirely.
+func NewAsyncGLMService(client *glm.Client, workers int, tenantRPS float64, tenantBurst int) *AsyncGLMService {
 	ctx, cancel := context.WithCancel(context.Background())
 
+	var tenantLimiter *ratelimit.Limiter
+	if tenantRPS > 0 {
+		tenantLimiter = ratelimit.NewLimiter(tenantBurst, tenantRPS)
+	}
+
 	service := &AsyncGLMService{
-		client:   client,
-		taskChan: make(chan GLMTask, 100),
-		ctx:      ctx,
-		cancel:   cancel,
+		client:        client,
+		highChan:      make(chan GLMTask, queueDepth),
+		normalChan:    make(chan GLMTask, queueDepth),
+		lowChan:       make(chan GLMTask, queueDepth),
+		tenantLimiter: tenantLimiter,
+		latencies: map[string]*latencyRing{
+			"summary": newLatencyRing(),
+			"tags":    newLatencyRing(),
+			"spam":    newLatencyRing(),
+		},
+		ctx:    ctx,
+		cancel: cancel,
 	}
 
 	for i := 0; i < workers; i++ {
@@ -122,23 +222,111 @@ func NewAsyncGLMService(client *glm.Client, workers int) *AsyncGLMService {
 	return service
 }
 
+func (s *AsyncGLMService) queueFor(p Priority) chan GLMTask {
+	switch p {
+	case PriorityHigh:
+		return s.highChan
+	case PriorityLow:
+		return s.lowChan
+	default:
+		return s.normalChan
+	}
+}
+
 func (s *AsyncGLMService) worker(id int) {
 	defer s.wg.Done()
 
+	scheduleIdx := 0
 	for {
 		select {
 		case <-s.ctx.Done():
 			log.Printf("GLM worker %d stopping", id)
 			return
-		case task := <-s.taskChan:
-			s.processTask(task, id)
+		default:
+		}
+
+		task, ok := s.dequeue(workerSchedule[scheduleIdx])
+		scheduleIdx = (scheduleIdx + 1) % len(workerSchedule)
+		if !ok {
+			return
+		}
+
+		if task.ctx != nil && task.ctx.Err() != nil {
+			task.Result <- TaskResult{ID: task.ID, Error: task.ctx.Err()}
+			continue
 		}
+
+		if task.TenantID != "" && s.tenantLimiter != nil && !s.tenantLimiter.Allow(task.TenantID) {
+			s.requeueThrottled(task)
+			continue
+		}
+
+		atomic.AddInt32(&s.inFlight, 1)
+		s.processTask(task, id)
+		atomic.AddInt32(&s.inFlight, -1)
 	}
 }
 
+// dequeue tries preferred first (the weighted round-robin's pick for this
+// turn), then cascades high -> normal -> low so an empty preferred queue
+// doesn't stall lower-priority work that's ready. If nothing is ready
+// anywhere, it blocks until a task arrives or the service is shutting down.
+func (s *AsyncGLMService) dequeue(preferred Priority) (GLMTask, bool) {
+	if t, ok := s.tryDequeue(preferred); ok {
+		return t, true
+	}
+	for _, p := range []Priority{PriorityHigh, PriorityNormal, PriorityLow} {
+		if p == preferred {
+			continue
+		}
+		if t, ok := s.tryDequeue(p); ok {
+			return t, true
+		}
+	}
+
+	select {
+	case t := <-s.highChan:
+		return t, true
+	case t := <-s.normalChan:
+		return t, true
+	case t := <-s.lowChan:
+		return t, true
+	case <-s.ctx.Done():
+		return GLMTask{}, false
+	}
+}
+
+func (s *AsyncGLMService) tryDequeue(p Priority) (GLMTask, bool) {
+	select {
+	case t := <-s.queueFor(p):
+		return t, true
+	default:
+		return GLMTask{}, false
+	}
+}
+
+// requeueThrottled re-enqueues task after tenantThrottleRetry, on its own
+// goroutine so the worker that hit the throttle can move on to other work
+// immediately instead of blocking.
+func (s *AsyncGLMService) requeueThrottled(task GLMTask) {
+	go func() {
+		timer := time.NewTimer(tenantThrottleRetry)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-s.ctx.Done():
+			return
+		}
+		select {
+		case s.queueFor(task.Priority) <- task:
+		case <-s.ctx.Done():
+		}
+	}()
+}
+
 func (s *AsyncGLMService) processTask(task GLMTask, workerID int) {
 	startTime := time.Now()
-	
+
 	log.Printf("[Worker %d] Processing task %s (type: %s)", workerID, task.ID, task.Type)
 
 	var result TaskResult
@@ -150,25 +338,29 @@ func (s *AsyncGLMService) processTask(task GLMTask, workerID int) {
 		data, err := s.client.SummarizeText(input.Content, input.MaxLength)
 		result.Data = data
 		result.Error = err
-		
+
 	case "tags":
 		input := task.Input.(TagsInput)
 		data, err := s.client.GenerateTags(input.Content, input.Count)
 		result.Data = data
 		result.Error = err
-		
+
 	case "spam":
 		input := task.Input.(SpamInput)
 		data, err := s.client.DetectSpam(input.Content)
 		result.Data = data
 		result.Error = err
-		
+
 	default:
 		result.Error = fmt.Errorf("unknown task type: %s", task.Type)
 	}
 
 	duration := time.Since(startTime)
-	
+
+	if ring, ok := s.latencies[task.Type]; ok {
+		ring.record(duration)
+	}
+
 	if result.Error != nil {
 		log.Printf("[Worker %d] Task %s failed after %v: %v", workerID, task.ID, duration, result.Error)
 	} else {
@@ -196,61 +388,152 @@ type SpamInput struct {
 	Content string
 }
 
-func (s *AsyncGLMService) SubmitSummary(taskID string, content string, maxLength int, resultChan chan<- TaskResult) {
+// SubmitWithContext enqueues task at its Priority (PriorityNormal if unset)
+// and TenantID, honoring ctx: if ctx is canceled before a worker starts the
+// task, the worker skips it and sends ctx.Err() to task.Result instead of
+// running it. A canceled ctx at enqueue time is reported the same way
+// Shutdown-during-enqueue always has been, via task.Result.
+func (s *AsyncGLMService) SubmitWithContext(ctx context.Context, task GLMTask) {
+	task.ctx = ctx
+
 	select {
-	case s.taskChan <- GLMTask{
-		ID:     taskID,
-		Type:   "summary",
-		Input:  SummaryInput{Content: content, MaxLength: maxLength},
-		Result: resultChan,
-	}:
+	case s.queueFor(task.Priority) <- task:
 	case <-s.ctx.Done():
-		resultChan <- TaskResult{ID: taskID, Error: fmt.Errorf("service is shutting down")}
+		task.Result <- TaskResult{ID: task.ID, Error: fmt.Errorf("service is shutting down")}
+	case <-ctx.Done():
+		task.Result <- TaskResult{ID: task.ID, Error: ctx.Err()}
 	}
 }
 
+func (s *AsyncGLMService) SubmitSummary(taskID string, content string, maxLength int, resultChan chan<- TaskResult) {
+	s.SubmitWithContext(context.Background(), GLMTask{
+		ID:       taskID,
+		Type:     "summary",
+		Priority: taskPriority["summary"],
+		Input:    SummaryInput{Content: content, MaxLength: maxLength},
+		Result:   resultChan,
+	})
+}
+
 func (s *AsyncGLMService) SubmitTags(taskID string, content string, count int, resultChan chan<- TaskResult) {
-	select {
-	case s.taskChan <- GLMTask{
-		ID:     taskID,
-		Type:   "tags",
-		Input:  TagsInput{Content: content, Count: count},
-		Result: resultChan,
-	}:
-	case <-s.ctx.Done():
-		resultChan <- TaskResult{ID: taskID, Error: fmt.Errorf("service is shutting down")}
-	}
+	s.SubmitWithContext(context.Background(), GLMTask{
+		ID:       taskID,
+		Type:     "tags",
+		Priority: taskPriority["tags"],
+		Input:    TagsInput{Content: content, Count: count},
+		Result:   resultChan,
+	})
 }
 
 func (s *AsyncGLMService) SubmitSpamCheck(taskID string, content string, resultChan chan<- TaskResult) {
-	select {
-	case s.taskChan <- GLMTask{
-		ID:     taskID,
-		Type:   "spam",
-		Input:  SpamInput{Content: content},
-		Result: resultChan,
-	}:
-	case <-s.ctx.Done():
-		resultChan <- TaskResult{ID: taskID, Error: fmt.Errorf("service is shutting down")}
+	s.SubmitWithContext(context.Background(), GLMTask{
+		ID:       taskID,
+		Type:     "spam",
+		Priority: taskPriority["spam"],
+		Input:    SpamInput{Content: content},
+		Result:   resultChan,
+	})
+}
+
+// QueueMetrics is a point-in-time snapshot returned by Metrics.
+type QueueMetrics struct {
+	// QueueDepth is the number of buffered (not yet dequeued) tasks per
+	// priority: "high", "normal", "low".
+	QueueDepth map[string]int
+	// InFlight is how many tasks workers are currently processing.
+	InFlight int
+	// Latency is keyed by task type ("summary", "tags", "spam").
+	Latency map[string]LatencyStats
+}
+
+// LatencyStats summarizes a task type's recent processing time, computed
+// from the last latencyRingSize samples.
+type LatencyStats struct {
+	P50   time.Duration
+	P95   time.Duration
+	Count int
+}
+
+func (s *AsyncGLMService) Metrics() QueueMetrics {
+	latency := make(map[string]LatencyStats, len(s.latencies))
+	for taskType, ring := range s.latencies {
+		p50, p95, count := ring.percentiles()
+		latency[taskType] = LatencyStats{P50: p50, P95: p95, Count: count}
+	}
+
+	return QueueMetrics{
+		QueueDepth: map[string]int{
+			"high":   len(s.highChan),
+			"normal": len(s.normalChan),
+			"low":    len(s.lowChan),
+		},
+		InFlight: int(atomic.LoadInt32(&s.inFlight)),
+		Latency:  latency,
+	}
+}
+
+// latencyRing is a fixed-size ring buffer of recent latency samples,
+// supporting p50/p95 computation without unbounded memory growth.
+type latencyRing struct {
+	mu      sync.Mutex
+	samples [latencyRingSize]time.Duration
+	next    int
+	count   int
+}
+
+func newLatencyRing() *latencyRing {
+	return &latencyRing{}
+}
+
+func (r *latencyRing) record(d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.samples[r.next] = d
+	r.next = (r.next + 1) % latencyRingSize
+	if r.count < latencyRingSize {
+		r.count++
 	}
 }
 
+func (r *latencyRing) percentiles() (p50, p95 time.Duration, count int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.count == 0 {
+		return 0, 0, 0
+	}
+
+	sorted := make([]time.Duration, r.count)
+	copy(sorted, r.samples[:r.count])
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	return sorted[percentileIndex(len(sorted), 0.50)], sorted[percentileIndex(len(sorted), 0.95)], r.count
+}
+
+func percentileIndex(n int, p float64) int {
+	idx := int(float64(n) * p)
+	if idx >= n {
+		idx = n - 1
+	}
+	return idx
+}
+
 func (s *AsyncGLMService) Shutdown() {
 	log.Println("Shutting down GLM service...")
-	
+
 	s.cancel()
-	close(s.taskChan)
-	
+
 	done := make(chan struct{})
 	go func() {
 		s.wg.Wait()
 		close(done)
 	}()
-	
+
 	select {
 	case <-done:
 		log.Println("GLM service shutdown complete")
 	case <-time.After(30 * time.Second):
 		log.Println("GLM service shutdown timeout")
 	}
-}
\ No newline at end of file
+}
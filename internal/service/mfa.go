@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cfrs2005/ppmtest/internal/mfa"
+	"github.com/cfrs2005/ppmtest/internal/models"
+)
+
+// ErrInvalidMFACode is returned by ConfirmMFA, DisableMFA and
+// CompleteMFALogin when code matches neither a current TOTP code nor an
+// unused backup code.
+var ErrInvalidMFACode = errors.New("invalid mfa code")
+
+// MFAService enrolls users in TOTP-based MFA and verifies the second factor
+// at login. It's embedded in UserService rather than split out as its own
+// top-level service since every method operates on a models.User the caller
+// already reached through UserService.
+type MFAService interface {
+	// EnrollMFA generates a new TOTP secret for userID and returns it along
+	// with the otpauth:// provisioning URI for the user's authenticator app.
+	// MFAEnabled stays false until ConfirmMFA proves the user captured the
+	// secret correctly.
+	EnrollMFA(ctx context.Context, userID uint) (secret, provisioningURI string, err error)
+	// ConfirmMFA verifies code against the secret EnrollMFA just generated,
+	// flips MFAEnabled on, and mints a fresh set of backup codes - returned
+	// as plaintext exactly once, the same "never stored, only shown" pattern
+	// mfa.GenerateBackupCodes documents.
+	ConfirmMFA(ctx context.Context, userID uint, code string) (backupCodes []string, err error)
+	// DisableMFA turns MFA off for userID after verifying code, and discards
+	// its backup codes.
+	DisableMFA(ctx context.Context, userID uint, code string) error
+	// CompleteMFALogin redeems the second factor for a login that returned
+	// ErrMFARequired, accepting either a current TOTP code or an unused
+	// backup code (which it consumes).
+	CompleteMFALogin(ctx context.Context, userID uint, code string) (*models.User, error)
+}
+
+func (s *userService) EnrollMFA(ctx context.Context, userID uint) (string, string, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return "", "", err
+	}
+
+	secret, err := mfa.GenerateSecret()
+	if err != nil {
+		return "", "", err
+	}
+
+	user.MFASecret = secret
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return "", "", err
+	}
+
+	return secret, mfa.ProvisioningURI(s.mfaConfig.Issuer, user.Email, secret), nil
+}
+
+func (s *userService) ConfirmMFA(ctx context.Context, userID uint, code string) ([]string, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.MFASecret == "" || !mfa.Validate(user.MFASecret, code, time.Now()) {
+		return nil, ErrInvalidMFACode
+	}
+
+	user.MFAEnabled = true
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	plainCodes, err := mfa.GenerateBackupCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]*models.MFABackupCode, len(plainCodes))
+	for i, plain := range plainCodes {
+		hash, err := mfa.HashBackupCode(plain)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = &models.MFABackupCode{UserID: userID, CodeHash: hash}
+	}
+
+	if err := s.backupCodeRepo.CreateBatch(ctx, records); err != nil {
+		return nil, err
+	}
+
+	return plainCodes, nil
+}
+
+func (s *userService) DisableMFA(ctx context.Context, userID uint, code string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !mfa.Validate(user.MFASecret, code, time.Now()) {
+		return ErrInvalidMFACode
+	}
+
+	user.MFAEnabled = false
+	user.MFASecret = ""
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	return s.backupCodeRepo.DeleteAllByUserID(ctx, userID)
+}
+
+func (s *userService) CompleteMFALogin(ctx context.Context, userID uint, code string) (*models.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if mfa.Validate(user.MFASecret, code, time.Now()) {
+		return user, nil
+	}
+
+	if s.consumeBackupCode(ctx, userID, code) {
+		return user, nil
+	}
+
+	return nil, ErrInvalidMFACode
+}
+
+// consumeBackupCode reports whether code matches one of userID's unused
+// backup codes, marking it used if so.
+func (s *userService) consumeBackupCode(ctx context.Context, userID uint, code string) bool {
+	codes, err := s.backupCodeRepo.ListUnusedByUserID(ctx, userID)
+	if err != nil {
+		return false
+	}
+
+	for _, c := range codes {
+		if mfa.CheckBackupCode(c.CodeHash, code) {
+			return s.backupCodeRepo.MarkUsed(ctx, c.ID) == nil
+		}
+	}
+	return false
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cfrs2005/ppmtest/internal/config"
+	"github.com/cfrs2005/ppmtest/internal/mfa"
+	"github.com/cfrs2005/ppmtest/internal/oidc"
+	"github.com/cfrs2005/ppmtest/internal/repository"
+	"github.com/cfrs2005/ppmtest/internal/testutil"
+)
+
+func TestUserService_Login_MFAChallenge_Integration(t *testing.T) {
+	db := testutil.NewDB(t)
+	userService := newTestUserService(db)
+	ctx := context.Background()
+
+	registered, err := userService.Register(ctx, "dave", "dave@integration.test", "password123")
+	if err != nil {
+		t.Fatalf("Register() error = %v, want nil", err)
+	}
+
+	secret, _, err := userService.EnrollMFA(ctx, registered.ID)
+	if err != nil {
+		t.Fatalf("EnrollMFA() error = %v, want nil", err)
+	}
+
+	code, err := mfa.CurrentCode(secret)
+	if err != nil {
+		t.Fatalf("mfa.CurrentCode() error = %v, want nil", err)
+	}
+	if _, err := userService.ConfirmMFA(ctx, registered.ID, code); err != nil {
+		t.Fatalf("ConfirmMFA() error = %v, want nil", err)
+	}
+
+	loggedIn, err := userService.Login(ctx, "dave@integration.test", "password123")
+	if !errors.Is(err, ErrMFARequired) {
+		t.Fatalf("Login() error = %v, want ErrMFARequired", err)
+	}
+	if loggedIn == nil || loggedIn.ID != registered.ID {
+		t.Fatalf("Login() returned user %v, want id %d alongside ErrMFARequired", loggedIn, registered.ID)
+	}
+
+	loginCode, err := mfa.CurrentCode(secret)
+	if err != nil {
+		t.Fatalf("mfa.CurrentCode() error = %v, want nil", err)
+	}
+	completed, err := userService.CompleteMFALogin(ctx, registered.ID, loginCode)
+	if err != nil {
+		t.Fatalf("CompleteMFALogin() error = %v, want nil", err)
+	}
+	if completed.ID != registered.ID {
+		t.Errorf("CompleteMFALogin() returned user %d, want %d", completed.ID, registered.ID)
+	}
+}
+
+func TestUserService_CompleteMFALogin_BackupCode_Integration(t *testing.T) {
+	db := testutil.NewDB(t)
+	userService := newTestUserService(db)
+	ctx := context.Background()
+
+	registered, err := userService.Register(ctx, "erin", "erin@integration.test", "password123")
+	if err != nil {
+		t.Fatalf("Register() error = %v, want nil", err)
+	}
+
+	secret, _, err := userService.EnrollMFA(ctx, registered.ID)
+	if err != nil {
+		t.Fatalf("EnrollMFA() error = %v, want nil", err)
+	}
+
+	enrollCode, err := mfa.CurrentCode(secret)
+	if err != nil {
+		t.Fatalf("mfa.CurrentCode() error = %v, want nil", err)
+	}
+	backupCodes, err := userService.ConfirmMFA(ctx, registered.ID, enrollCode)
+	if err != nil {
+		t.Fatalf("ConfirmMFA() error = %v, want nil", err)
+	}
+	if len(backupCodes) == 0 {
+		t.Fatal("ConfirmMFA() returned no backup codes")
+	}
+
+	code := backupCodes[0]
+	if _, err := userService.CompleteMFALogin(ctx, registered.ID, code); err != nil {
+		t.Fatalf("CompleteMFALogin(backup code) error = %v, want nil", err)
+	}
+
+	// A consumed backup code can't be reused.
+	if _, err := userService.CompleteMFALogin(ctx, registered.ID, code); !errors.Is(err, ErrInvalidMFACode) {
+		t.Errorf("CompleteMFALogin(reused backup code) error = %v, want ErrInvalidMFACode", err)
+	}
+}
+
+func TestUserService_OIDC_LinkUnlink_Integration(t *testing.T) {
+	db := testutil.NewDB(t)
+	userService := newTestUserService(db)
+	ctx := context.Background()
+
+	registered, err := userService.Register(ctx, "frank", "frank@integration.test", "password123")
+	if err != nil {
+		t.Fatalf("Register() error = %v, want nil", err)
+	}
+
+	if err := userService.LinkIdentity(ctx, registered.ID, "google", "google-sub-123", "frank@integration.test"); err != nil {
+		t.Fatalf("LinkIdentity() error = %v, want nil", err)
+	}
+
+	linked, err := userService.FindUserByIdentity(ctx, "google", "google-sub-123")
+	if err != nil {
+		t.Fatalf("FindUserByIdentity() error = %v, want nil", err)
+	}
+	if linked.ID != registered.ID {
+		t.Errorf("FindUserByIdentity() returned user %d, want %d", linked.ID, registered.ID)
+	}
+
+	identities, err := userService.ListIdentities(ctx, registered.ID)
+	if err != nil {
+		t.Fatalf("ListIdentities() error = %v, want nil", err)
+	}
+	if len(identities) != 1 {
+		t.Fatalf("ListIdentities() returned %d identities, want 1", len(identities))
+	}
+
+	if err := userService.UnlinkIdentity(ctx, registered.ID, "google"); err != nil {
+		t.Fatalf("UnlinkIdentity() error = %v, want nil", err)
+	}
+
+	if _, err := userService.FindUserByIdentity(ctx, "google", "google-sub-123"); err == nil {
+		t.Error("FindUserByIdentity() after UnlinkIdentity() error = nil, want not found")
+	}
+}
+
+func TestUserService_MatchUserByEmail_EmailFilter_Integration(t *testing.T) {
+	db := testutil.NewDB(t)
+	userService := NewUserService(
+		repository.NewUserRepository(db),
+		repository.NewMFABackupCodeRepository(db),
+		repository.NewUserIdentityRepository(db),
+		config.MFAConfig{Issuer: "Test Blog"},
+		oidc.EmailFilter{Aliases: map[string]string{"grace@corp.example": "grace@personal.example"}},
+	)
+	ctx := context.Background()
+
+	registered, err := userService.Register(ctx, "grace", "grace@personal.example", "password123")
+	if err != nil {
+		t.Fatalf("Register() error = %v, want nil", err)
+	}
+
+	matched, err := userService.MatchUserByEmail(ctx, "grace@corp.example")
+	if err != nil {
+		t.Fatalf("MatchUserByEmail() error = %v, want nil", err)
+	}
+	if matched.ID != registered.ID {
+		t.Errorf("MatchUserByEmail() returned user %d, want %d", matched.ID, registered.ID)
+	}
+}
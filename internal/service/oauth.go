@@ -0,0 +1,297 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/cfrs2005/ppmtest/internal/auth/jwt"
+	"github.com/cfrs2005/ppmtest/internal/models"
+	"github.com/cfrs2005/ppmtest/internal/repository"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrInvalidClient      = errors.New("invalid client credentials")
+	ErrInvalidRedirectURI = errors.New("redirect_uri is not registered for this client")
+	ErrInvalidScope       = errors.New("requested scope exceeds the client's allowed scopes")
+	ErrInvalidGrant       = errors.New("invalid or expired authorization code")
+	ErrInvalidPKCE        = errors.New("code_verifier does not match code_challenge")
+	ErrUnsupportedGrant   = errors.New("unsupported grant_type")
+	ErrMismatchedClient   = errors.New("authorization code was not issued to this client")
+)
+
+const authorizationCodeTTL = 10 * time.Minute
+
+// AuthorizeRequest is the validated query of GET /oauth/authorize.
+type AuthorizeRequest struct {
+	ClientID            string
+	RedirectURI         string
+	Scope               string
+	State               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+}
+
+// Consent is what a consent-page handler needs to render: who's asking, and
+// for what.
+type Consent struct {
+	Client *models.OAuthClient
+	Scopes []string
+}
+
+// TokenResult is the response body shape for POST /oauth/token.
+type TokenResult struct {
+	AccessToken  string
+	RefreshToken string
+	TokenType    string
+	ExpiresIn    int
+	Scope        string
+}
+
+type OAuthService interface {
+	// Authorize validates response_type, client_id, redirect_uri and scope
+	// and returns the consent info for req.ClientID.
+	Authorize(ctx context.Context, req AuthorizeRequest) (*Consent, error)
+
+	// IssueCode mints an authorization code for userID after the resource
+	// owner has approved req's client/scope.
+	IssueCode(ctx context.Context, req AuthorizeRequest, userID uint) (code string, err error)
+
+	// ExchangeAuthorizationCode redeems code for a token pair (the
+	// authorization_code grant).
+	ExchangeAuthorizationCode(ctx context.Context, clientID, clientSecret, code, redirectURI, codeVerifier string) (*TokenResult, error)
+
+	// RefreshToken rotates refreshToken for a new token pair (the
+	// refresh_token grant).
+	RefreshToken(ctx context.Context, clientID, clientSecret, refreshToken string) (*TokenResult, error)
+
+	// ClientCredentials issues a scoped, user-less access token for
+	// machine-to-machine callers (the client_credentials grant).
+	ClientCredentials(ctx context.Context, clientID, clientSecret, scope string) (*TokenResult, error)
+}
+
+type oauthService struct {
+	clientRepo repository.OAuthClientRepository
+	codeRepo   repository.AuthorizationCodeRepository
+	tokens     *jwt.Service
+}
+
+func NewOAuthService(clientRepo repository.OAuthClientRepository, codeRepo repository.AuthorizationCodeRepository, tokens *jwt.Service) OAuthService {
+	return &oauthService{
+		clientRepo: clientRepo,
+		codeRepo:   codeRepo,
+		tokens:     tokens,
+	}
+}
+
+func splitScope(scope string) []string {
+	return strings.Fields(scope)
+}
+
+func scopeAllowed(requested, allowed string) bool {
+	allowedSet := make(map[string]struct{})
+	for _, s := range splitScope(allowed) {
+		allowedSet[s] = struct{}{}
+	}
+	for _, s := range splitScope(requested) {
+		if _, ok := allowedSet[s]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func redirectURIAllowed(redirectURI, registered string) bool {
+	for _, uri := range strings.Split(registered, "\n") {
+		if strings.TrimSpace(uri) == redirectURI {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *oauthService) lookupClient(ctx context.Context, clientID, clientSecret string) (*models.OAuthClient, error) {
+	client, err := s.clientRepo.GetByClientID(ctx, clientID)
+	if err != nil {
+		if errors.Is(err, repository.ErrOAuthClientNotFound) {
+			return nil, ErrInvalidClient
+		}
+		return nil, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(client.ClientSecret), []byte(clientSecret)); err != nil {
+		return nil, ErrInvalidClient
+	}
+
+	return client, nil
+}
+
+func (s *oauthService) Authorize(ctx context.Context, req AuthorizeRequest) (*Consent, error) {
+	client, err := s.clientRepo.GetByClientID(ctx, req.ClientID)
+	if err != nil {
+		if errors.Is(err, repository.ErrOAuthClientNotFound) {
+			return nil, ErrInvalidClient
+		}
+		return nil, err
+	}
+
+	if !redirectURIAllowed(req.RedirectURI, client.RedirectURIs) {
+		return nil, ErrInvalidRedirectURI
+	}
+
+	if !scopeAllowed(req.Scope, client.AllowedScopes) {
+		return nil, ErrInvalidScope
+	}
+
+	return &Consent{Client: client, Scopes: splitScope(req.Scope)}, nil
+}
+
+func (s *oauthService) IssueCode(ctx context.Context, req AuthorizeRequest, userID uint) (string, error) {
+	if _, err := s.Authorize(ctx, req); err != nil {
+		return "", err
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	code := hex.EncodeToString(raw)
+
+	authCode := &models.AuthorizationCode{
+		Code:                code,
+		ClientID:            req.ClientID,
+		UserID:              userID,
+		RedirectURI:         req.RedirectURI,
+		Scope:               req.Scope,
+		CodeChallenge:       req.CodeChallenge,
+		CodeChallengeMethod: req.CodeChallengeMethod,
+		ExpiresAt:           time.Now().Add(authorizationCodeTTL),
+	}
+
+	if err := s.codeRepo.Create(ctx, authCode); err != nil {
+		return "", err
+	}
+
+	return code, nil
+}
+
+func verifyPKCE(authCode *models.AuthorizationCode, codeVerifier string) error {
+	if authCode.CodeChallenge == "" {
+		return nil
+	}
+
+	var computed string
+	switch authCode.CodeChallengeMethod {
+	case "S256", "":
+		sum := sha256.Sum256([]byte(codeVerifier))
+		computed = base64.RawURLEncoding.EncodeToString(sum[:])
+	case "plain":
+		computed = codeVerifier
+	default:
+		return ErrInvalidPKCE
+	}
+
+	if subtle.ConstantTimeCompare([]byte(computed), []byte(authCode.CodeChallenge)) != 1 {
+		return ErrInvalidPKCE
+	}
+	return nil
+}
+
+func (s *oauthService) ExchangeAuthorizationCode(ctx context.Context, clientID, clientSecret, code, redirectURI, codeVerifier string) (*TokenResult, error) {
+	client, err := s.lookupClient(ctx, clientID, clientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	authCode, err := s.codeRepo.Consume(ctx, code)
+	if err != nil {
+		if errors.Is(err, repository.ErrAuthorizationCodeNotFound) {
+			return nil, ErrInvalidGrant
+		}
+		return nil, err
+	}
+
+	if authCode.ClientID != client.ClientID {
+		return nil, ErrMismatchedClient
+	}
+	if time.Now().After(authCode.ExpiresAt) {
+		return nil, ErrInvalidGrant
+	}
+	if authCode.RedirectURI != redirectURI {
+		return nil, ErrInvalidRedirectURI
+	}
+	if err := verifyPKCE(authCode, codeVerifier); err != nil {
+		return nil, err
+	}
+
+	access, refresh, err := s.tokens.IssueClientTokenPair(authCode.UserID, "oauth", client.ClientID, authCode.Scope)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenResult{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(s.tokens.AccessTTL().Seconds()),
+		Scope:        authCode.Scope,
+	}, nil
+}
+
+func (s *oauthService) RefreshToken(ctx context.Context, clientID, clientSecret, refreshToken string) (*TokenResult, error) {
+	client, err := s.lookupClient(ctx, clientID, clientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := s.tokens.PeekClientID(refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	if claims != client.ClientID {
+		return nil, ErrMismatchedClient
+	}
+
+	access, refresh, err := s.tokens.Refresh(refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenResult{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(s.tokens.AccessTTL().Seconds()),
+	}, nil
+}
+
+func (s *oauthService) ClientCredentials(ctx context.Context, clientID, clientSecret, scope string) (*TokenResult, error) {
+	client, err := s.lookupClient(ctx, clientID, clientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	if !scopeAllowed(scope, client.AllowedScopes) {
+		return nil, ErrInvalidScope
+	}
+
+	access, err := s.tokens.IssueAccessToken(0, "service", client.ClientID, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenResult{
+		AccessToken: access,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(s.tokens.AccessTTL().Seconds()),
+		Scope:       scope,
+	}, nil
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+
+	"github.com/cfrs2005/ppmtest/internal/models"
+	"github.com/cfrs2005/ppmtest/internal/repository"
+)
+
+// ErrIdentityEmailNotFound is returned by LinkIdentity when no local user
+// matches the identity's email (after EmailFilter.Apply), so the caller
+// knows to fall back to Register instead of silently failing to link.
+var ErrIdentityEmailNotFound = repository.ErrUserNotFound
+
+// OIDCService links and unlinks external OIDC/OAuth2 identities (Google,
+// GitHub, ...) to local accounts. The authorization-code exchange and
+// userinfo fetch themselves live in internal/oidc.Provider; this service
+// only resolves an already-fetched internal/oidc.Identity to a local user.
+type OIDCService interface {
+	// FindUserByIdentity looks up the local user already linked to
+	// provider/providerUserID, for returning sign-ins.
+	FindUserByIdentity(ctx context.Context, provider, providerUserID string) (*models.User, error)
+	// MatchUserByEmail runs email through the configured EmailFilter and
+	// looks up the resulting local account, for first-time sign-ins that
+	// haven't been linked yet.
+	MatchUserByEmail(ctx context.Context, email string) (*models.User, error)
+	// LinkIdentity links provider/providerUserID to userID, so future
+	// sign-ins from that provider resolve via FindUserByIdentity.
+	LinkIdentity(ctx context.Context, userID uint, provider, providerUserID, email string) error
+	// UnlinkIdentity removes userID's link to provider.
+	UnlinkIdentity(ctx context.Context, userID uint, provider string) error
+	// ListIdentities returns every external identity linked to userID.
+	ListIdentities(ctx context.Context, userID uint) ([]*models.UserIdentity, error)
+}
+
+func (s *userService) FindUserByIdentity(ctx context.Context, provider, providerUserID string) (*models.User, error) {
+	identity, err := s.identityRepo.GetByProvider(ctx, provider, providerUserID)
+	if err != nil {
+		return nil, err
+	}
+	return s.userRepo.GetByID(ctx, identity.UserID)
+}
+
+func (s *userService) MatchUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	return s.userRepo.GetByEmail(ctx, s.emailFilter.Apply(email))
+}
+
+func (s *userService) LinkIdentity(ctx context.Context, userID uint, provider, providerUserID, email string) error {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return err
+	}
+
+	return s.identityRepo.Create(ctx, &models.UserIdentity{
+		UserID:         userID,
+		Provider:       provider,
+		ProviderUserID: providerUserID,
+		Email:          email,
+	})
+}
+
+func (s *userService) UnlinkIdentity(ctx context.Context, userID uint, provider string) error {
+	return s.identityRepo.Delete(ctx, userID, provider)
+}
+
+func (s *userService) ListIdentities(ctx context.Context, userID uint) ([]*models.UserIdentity, error) {
+	return s.identityRepo.ListByUserID(ctx, userID)
+}
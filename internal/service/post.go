@@ -1,12 +1,16 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"strings"
 	"time"
 
-	"ppmtest/internal/models"
-	"ppmtest/internal/repository"
+	"github.com/cfrs2005/ppmtest/internal/models"
+	"github.com/cfrs2005/ppmtest/internal/repository"
+
+	"github.com/cfrs2005/ppmtest/internal/metrics"
+	"github.com/cfrs2005/ppmtest/internal/search"
 )
 
 var (
@@ -14,29 +18,32 @@ var (
 )
 
 type PostService interface {
-	Create(post *models.Post, authorID uint) error
-	GetByID(id uint) (*models.Post, error)
-	GetBySlug(slug string) (*models.Post, error)
-	Update(post *models.Post, userID uint) error
-	Delete(id uint, userID uint) error
-	List(page, pageSize int) ([]*models.Post, int64, error)
-	GetByAuthorID(authorID uint, page, pageSize int) ([]*models.Post, int64, error)
-	GetByStatus(status string, page, pageSize int) ([]*models.Post, int64, error)
-	Search(query string, page, pageSize int) ([]*models.Post, int64, error)
-	Publish(id uint, userID uint) error
+	Create(ctx context.Context, post *models.Post, authorID uint) error
+	GetByID(ctx context.Context, id uint) (*models.Post, error)
+	GetBySlug(ctx context.Context, slug string) (*models.Post, error)
+	Update(ctx context.Context, post *models.Post, userID uint) error
+	Delete(ctx context.Context, id uint, userID uint) error
+	List(ctx context.Context, page, pageSize int, filter repository.PostFilter) ([]*models.Post, int64, error)
+	GetByAuthorID(ctx context.Context, authorID uint, page, pageSize int) ([]*models.Post, int64, error)
+	GetByStatus(ctx context.Context, status string, page, pageSize int) ([]*models.Post, int64, error)
+	Search(ctx context.Context, page, pageSize int, opts search.Options) ([]search.Hit, int64, error)
+	Publish(ctx context.Context, id uint, userID uint) error
+	Archive(ctx context.Context, id uint, userID uint) error
 }
 
 type postService struct {
-	postRepo repository.PostRepository
+	postRepo     repository.PostRepository
+	searchEngine search.Engine
 }
 
-func NewPostService(postRepo repository.PostRepository) PostService {
+func NewPostService(postRepo repository.PostRepository, searchEngine search.Engine) PostService {
 	return &postService{
-		postRepo: postRepo,
+		postRepo:     postRepo,
+		searchEngine: searchEngine,
 	}
 }
 
-func (s *postService) Create(post *models.Post, authorID uint) error {
+func (s *postService) Create(ctx context.Context, post *models.Post, authorID uint) error {
 	post.AuthorID = authorID
 	post.Status = "draft"
 
@@ -44,19 +51,24 @@ func (s *postService) Create(post *models.Post, authorID uint) error {
 		post.Slug = generateSlug(post.Title)
 	}
 
-	return s.postRepo.Create(post)
+	if err := s.postRepo.Create(ctx, post); err != nil {
+		return err
+	}
+
+	metrics.PostsCreatedTotal.Inc()
+	return nil
 }
 
-func (s *postService) GetByID(id uint) (*models.Post, error) {
-	return s.postRepo.GetByID(id)
+func (s *postService) GetByID(ctx context.Context, id uint) (*models.Post, error) {
+	return s.postRepo.FindByID(ctx, id)
 }
 
-func (s *postService) GetBySlug(slug string) (*models.Post, error) {
-	return s.postRepo.GetBySlug(slug)
+func (s *postService) GetBySlug(ctx context.Context, slug string) (*models.Post, error) {
+	return s.postRepo.FindBySlug(ctx, slug)
 }
 
-func (s *postService) Update(post *models.Post, userID uint) error {
-	existingPost, err := s.postRepo.GetByID(post.ID)
+func (s *postService) Update(ctx context.Context, post *models.Post, userID uint) error {
+	existingPost, err := s.postRepo.FindByID(ctx, post.ID)
 	if err != nil {
 		return err
 	}
@@ -69,11 +81,11 @@ func (s *postService) Update(post *models.Post, userID uint) error {
 		post.Slug = generateSlug(post.Title)
 	}
 
-	return s.postRepo.Update(post)
+	return s.postRepo.Update(ctx, post)
 }
 
-func (s *postService) Delete(id uint, userID uint) error {
-	post, err := s.postRepo.GetByID(id)
+func (s *postService) Delete(ctx context.Context, id uint, userID uint) error {
+	post, err := s.postRepo.FindByID(ctx, id)
 	if err != nil {
 		return err
 	}
@@ -82,31 +94,37 @@ func (s *postService) Delete(id uint, userID uint) error {
 		return ErrUnauthorizedPost
 	}
 
-	return s.postRepo.Delete(id)
+	return s.postRepo.Delete(ctx, id)
 }
 
-func (s *postService) List(page, pageSize int) ([]*models.Post, int64, error) {
+func (s *postService) List(ctx context.Context, page, pageSize int, filter repository.PostFilter) ([]*models.Post, int64, error) {
 	offset := (page - 1) * pageSize
-	return s.postRepo.List(offset, pageSize)
+	return s.postRepo.List(ctx, offset, pageSize, filter)
 }
 
-func (s *postService) GetByAuthorID(authorID uint, page, pageSize int) ([]*models.Post, int64, error) {
+func (s *postService) GetByAuthorID(ctx context.Context, authorID uint, page, pageSize int) ([]*models.Post, int64, error) {
 	offset := (page - 1) * pageSize
-	return s.postRepo.GetByAuthorID(authorID, offset, pageSize)
+	return s.postRepo.FindByAuthor(ctx, authorID, offset, pageSize)
 }
 
-func (s *postService) GetByStatus(status string, page, pageSize int) ([]*models.Post, int64, error) {
+func (s *postService) GetByStatus(ctx context.Context, status string, page, pageSize int) ([]*models.Post, int64, error) {
 	offset := (page - 1) * pageSize
-	return s.postRepo.GetByStatus(status, offset, pageSize)
+	return s.postRepo.List(ctx, offset, pageSize, repository.PostFilter{Status: status})
 }
 
-func (s *postService) Search(query string, page, pageSize int) ([]*models.Post, int64, error) {
-	offset := (page - 1) * pageSize
-	return s.postRepo.Search(query, offset, pageSize)
+func (s *postService) Search(ctx context.Context, page, pageSize int, opts search.Options) ([]search.Hit, int64, error) {
+	if opts.Query == "" {
+		return []search.Hit{}, 0, nil
+	}
+
+	opts.Offset = (page - 1) * pageSize
+	opts.Limit = pageSize
+
+	return s.searchEngine.Search(ctx, opts)
 }
 
-func (s *postService) Publish(id uint, userID uint) error {
-	post, err := s.postRepo.GetByID(id)
+func (s *postService) Publish(ctx context.Context, id uint, userID uint) error {
+	post, err := s.postRepo.FindByID(ctx, id)
 	if err != nil {
 		return err
 	}
@@ -119,7 +137,25 @@ func (s *postService) Publish(id uint, userID uint) error {
 	now := time.Now()
 	post.PublishedAt = &now
 
-	return s.postRepo.Update(post)
+	return s.postRepo.Update(ctx, post)
+}
+
+// Archive marks a post as archived, hiding it from normal listings. Like
+// Publish, it's author-gated; reportService.Resolve calls it on the post's
+// own author (fetched via GetByID) to take a reported post down as part of
+// the moderation workflow.
+func (s *postService) Archive(ctx context.Context, id uint, userID uint) error {
+	post, err := s.postRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if post.AuthorID != userID {
+		return ErrUnauthorizedPost
+	}
+
+	post.Status = "archived"
+	return s.postRepo.Update(ctx, post)
 }
 
 func generateSlug(title string) string {
@@ -128,7 +164,7 @@ func generateSlug(title string) string {
 	slug = strings.ReplaceAll(slug, "?", "")
 	slug = strings.ReplaceAll(slug, "!", "")
 	slug = strings.ReplaceAll(slug, ".", "")
-	
+
 	if len(slug) > 100 {
 		slug = slug[:100]
 	}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cfrs2005/ppmtest/internal/models"
+	"github.com/cfrs2005/ppmtest/internal/repository"
+)
+
+var (
+	ErrReportAlreadyExists = errors.New("an open report already exists for this reporter and target")
+	ErrInvalidTargetType   = errors.New("invalid report target type")
+)
+
+// defaultAutoHideThreshold bounds how many pending reports a single target
+// may accumulate before CreateReport hides it automatically, mirroring
+// commentService's defaultPendingFloodThreshold gate.
+const defaultAutoHideThreshold = 3
+
+// Resolve actions a moderator may request alongside closing out a report.
+const (
+	ResolveActionNone = ""
+	ResolveActionHide = "hide"
+)
+
+type ReportService interface {
+	CreateReport(ctx context.Context, reporterID uint, targetType models.ReportTargetType, targetID uint, reason models.ReportReason, description string) (*models.Report, error)
+	ListPending(ctx context.Context, page, pageSize int) ([]*models.Report, int64, error)
+	Resolve(ctx context.Context, id uint, handlerID uint, action string) error
+	Dismiss(ctx context.Context, id uint, handlerID uint) error
+}
+
+type reportService struct {
+	reportRepo     repository.ReportRepository
+	postService    PostService
+	commentService CommentService
+	autoHideThresh int
+}
+
+func NewReportService(reportRepo repository.ReportRepository, postService PostService, commentService CommentService) ReportService {
+	return &reportService{
+		reportRepo:     reportRepo,
+		postService:    postService,
+		commentService: commentService,
+		autoHideThresh: defaultAutoHideThreshold,
+	}
+}
+
+func (s *reportService) CreateReport(ctx context.Context, reporterID uint, targetType models.ReportTargetType, targetID uint, reason models.ReportReason, description string) (*models.Report, error) {
+	switch targetType {
+	case models.ReportTargetPost, models.ReportTargetComment, models.ReportTargetUser:
+	default:
+		return nil, ErrInvalidTargetType
+	}
+
+	_, err := s.reportRepo.GetOpenByReporterAndTarget(ctx, reporterID, targetType, targetID)
+	if err == nil {
+		return nil, ErrReportAlreadyExists
+	}
+	if !errors.Is(err, repository.ErrReportNotFound) {
+		return nil, err
+	}
+
+	report := &models.Report{
+		ReporterID:  reporterID,
+		TargetType:  targetType,
+		TargetID:    targetID,
+		Reason:      reason,
+		Description: description,
+		Status:      models.ReportStatusPending,
+	}
+
+	if err := s.reportRepo.Create(ctx, report); err != nil {
+		return nil, err
+	}
+
+	pending, err := s.reportRepo.CountPendingByTarget(ctx, targetType, targetID)
+	if err == nil && pending > int64(s.autoHideThresh) {
+		s.hideTarget(ctx, targetType, targetID)
+	}
+
+	return report, nil
+}
+
+func (s *reportService) ListPending(ctx context.Context, page, pageSize int) ([]*models.Report, int64, error) {
+	offset := (page - 1) * pageSize
+	return s.reportRepo.List(ctx, string(models.ReportStatusPending), offset, pageSize)
+}
+
+func (s *reportService) Resolve(ctx context.Context, id uint, handlerID uint, action string) error {
+	report, err := s.reportRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if action == ResolveActionHide {
+		s.hideTarget(ctx, report.TargetType, report.TargetID)
+	}
+
+	now := time.Now()
+	report.Status = models.ReportStatusResolved
+	report.HandlerID = &handlerID
+	report.HandledAt = &now
+
+	return s.reportRepo.Update(ctx, report)
+}
+
+func (s *reportService) Dismiss(ctx context.Context, id uint, handlerID uint) error {
+	report, err := s.reportRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	report.Status = models.ReportStatusDismissed
+	report.HandlerID = &handlerID
+	report.HandledAt = &now
+
+	return s.reportRepo.Update(ctx, report)
+}
+
+// hideTarget takes a reported post or comment out of normal circulation by
+// routing through the existing author/moderator-facing services, rather
+// than reaching into their repositories directly. Errors are deliberately
+// swallowed: hiding is a best-effort side effect of the report workflow,
+// not something that should fail the report write it accompanies. There's
+// no equivalent action for a reported user yet, so ReportTargetUser is a
+// no-op here.
+func (s *reportService) hideTarget(ctx context.Context, targetType models.ReportTargetType, targetID uint) {
+	switch targetType {
+	case models.ReportTargetPost:
+		post, err := s.postService.GetByID(ctx, targetID)
+		if err != nil {
+			return
+		}
+		_ = s.postService.Archive(ctx, targetID, post.AuthorID)
+	case models.ReportTargetComment:
+		_ = s.commentService.MarkAsSpam(ctx, targetID)
+	}
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cfrs2005/ppmtest/internal/models"
+	"github.com/cfrs2005/ppmtest/internal/repository"
+)
+
+var (
+	ErrTagNameRequired = errors.New("tag name is required")
+)
+
+// TagService defines the interface for tag business logic
+type TagService interface {
+	CreateTag(ctx context.Context, tag *models.Tag) error
+	GetTagByID(ctx context.Context, id uint) (*models.Tag, error)
+	GetTagBySlug(ctx context.Context, slug string) (*models.Tag, error)
+	UpdateTag(ctx context.Context, tag *models.Tag) error
+	DeleteTag(ctx context.Context, id uint) error
+	ListTags(ctx context.Context, page, pageSize int) ([]*models.Tag, int64, error)
+}
+
+type tagService struct {
+	tagRepo repository.TagRepository
+}
+
+// NewTagService creates a new tag service
+func NewTagService(tagRepo repository.TagRepository) TagService {
+	return &tagService{tagRepo: tagRepo}
+}
+
+func (s *tagService) CreateTag(ctx context.Context, tag *models.Tag) error {
+	if tag.Name == "" {
+		return ErrTagNameRequired
+	}
+
+	if tag.Slug == "" {
+		tag.Slug = generateSlug(tag.Name)
+	}
+
+	return s.tagRepo.Create(ctx, tag)
+}
+
+func (s *tagService) GetTagByID(ctx context.Context, id uint) (*models.Tag, error) {
+	return s.tagRepo.FindByID(ctx, id)
+}
+
+func (s *tagService) GetTagBySlug(ctx context.Context, slug string) (*models.Tag, error) {
+	return s.tagRepo.FindBySlug(ctx, slug)
+}
+
+func (s *tagService) UpdateTag(ctx context.Context, tag *models.Tag) error {
+	if tag.Name == "" {
+		return ErrTagNameRequired
+	}
+
+	return s.tagRepo.Update(ctx, tag)
+}
+
+func (s *tagService) DeleteTag(ctx context.Context, id uint) error {
+	return s.tagRepo.Delete(ctx, id)
+}
+
+func (s *tagService) ListTags(ctx context.Context, page, pageSize int) ([]*models.Tag, int64, error) {
+	offset := (page - 1) * pageSize
+	return s.tagRepo.List(ctx, offset, pageSize)
+}
@@ -1,39 +1,90 @@
 package service
 
 import (
+	"context"
 	"errors"
 
-	"ppmtest/internal/models"
-	"ppmtest/internal/repository"
+	"github.com/cfrs2005/ppmtest/internal/config"
+	"github.com/cfrs2005/ppmtest/internal/models"
+	"github.com/cfrs2005/ppmtest/internal/repository"
 
+	"github.com/cfrs2005/ppmtest/internal/metrics"
 	"golang.org/x/crypto/bcrypt"
 )
 
 var (
 	ErrInvalidCredentials = errors.New("invalid credentials")
 	ErrUnauthorized       = errors.New("unauthorized")
+
+	// ErrMFAEnrollmentRequired is returned by Login when the user's role is
+	// in MFAConfig.RequiredRoles but they haven't run EnrollMFA/ConfirmMFA
+	// yet - their password is correct, but they can't finish logging in
+	// until MFA is set up.
+	ErrMFAEnrollmentRequired = errors.New("mfa enrollment is required for this account before logging in")
+	// ErrMFARequired is returned by Login alongside the matched user (a
+	// deliberate exception to this file's usual "nil user on error"
+	// convention - the caller needs the user's ID to issue an MFA
+	// challenge token) when the password was correct but a TOTP code or
+	// backup code still needs to be verified via CompleteMFALogin.
+	ErrMFARequired = errors.New("mfa verification required")
 )
 
 type UserService interface {
-	Register(username, email, password string) (*models.User, error)
-	Login(email, password string) (*models.User, error)
-	GetByID(id uint) (*models.User, error)
-	Update(user *models.User) error
-	Delete(id uint) error
-	List(page, pageSize int) ([]*models.User, int64, error)
+	Register(ctx context.Context, username, email, password string) (*models.User, error)
+	Login(ctx context.Context, email, password string) (*models.User, error)
+	GetByID(ctx context.Context, id uint) (*models.User, error)
+	Update(ctx context.Context, user *models.User) error
+	Delete(ctx context.Context, id uint) error
+	List(ctx context.Context, page, pageSize int) ([]*models.User, int64, error)
+	ChangePassword(ctx context.Context, userID uint, oldPassword, newPassword string) error
+
+	MFAService
+	OIDCService
 }
 
 type userService struct {
-	userRepo repository.UserRepository
+	userRepo       repository.UserRepository
+	backupCodeRepo repository.MFABackupCodeRepository
+	identityRepo   repository.UserIdentityRepository
+	mfaConfig      config.MFAConfig
+	emailFilter    EmailFilter
+}
+
+// EmailFilter is satisfied by oidc.EmailFilter; UserService depends on the
+// interface rather than the concrete type so OIDCService's tests don't need
+// a real internal/oidc.EmailFilter.
+type EmailFilter interface {
+	Apply(email string) string
 }
 
-func NewUserService(userRepo repository.UserRepository) UserService {
+func NewUserService(
+	userRepo repository.UserRepository,
+	backupCodeRepo repository.MFABackupCodeRepository,
+	identityRepo repository.UserIdentityRepository,
+	mfaConfig config.MFAConfig,
+	emailFilter EmailFilter,
+) UserService {
 	return &userService{
-		userRepo: userRepo,
+		userRepo:       userRepo,
+		backupCodeRepo: backupCodeRepo,
+		identityRepo:   identityRepo,
+		mfaConfig:      mfaConfig,
+		emailFilter:    emailFilter,
 	}
 }
 
-func (s *userService) Register(username, email, password string) (*models.User, error) {
+// requiresMFA reports whether role must have MFA enrolled before it can log
+// in, per MFAConfig.RequiredRoles.
+func (s *userService) requiresMFA(role string) bool {
+	for _, r := range s.mfaConfig.RequiredRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *userService) Register(ctx context.Context, username, email, password string) (*models.User, error) {
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
 		return nil, err
@@ -47,46 +98,81 @@ func (s *userService) Register(username, email, password string) (*models.User,
 		Status:   "active",
 	}
 
-	if err := s.userRepo.Create(user); err != nil {
+	if err := s.userRepo.Create(ctx, user); err != nil {
 		return nil, err
 	}
 
 	return user, nil
 }
 
-func (s *userService) Login(email, password string) (*models.User, error) {
-	user, err := s.userRepo.GetByEmail(email)
+func (s *userService) Login(ctx context.Context, email, password string) (*models.User, error) {
+	user, err := s.userRepo.GetByEmail(ctx, email)
 	if err != nil {
 		if errors.Is(err, repository.ErrUserNotFound) {
+			metrics.LoginsTotal.Inc("failure")
 			return nil, ErrInvalidCredentials
 		}
 		return nil, err
 	}
 
 	if user.Status != "active" {
+		metrics.LoginsTotal.Inc("failure")
 		return nil, ErrUnauthorized
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		metrics.LoginsTotal.Inc("failure")
 		return nil, ErrInvalidCredentials
 	}
 
+	if !user.MFAEnabled && s.requiresMFA(user.Role) {
+		metrics.LoginsTotal.Inc("failure")
+		return nil, ErrMFAEnrollmentRequired
+	}
+
+	if user.MFAEnabled {
+		// Password check passed, but the caller still needs to redeem an
+		// MFA challenge token via CompleteMFALogin - metrics record the
+		// login as successful once that second factor clears.
+		return user, ErrMFARequired
+	}
+
+	metrics.LoginsTotal.Inc("success")
 	return user, nil
 }
 
-func (s *userService) GetByID(id uint) (*models.User, error) {
-	return s.userRepo.GetByID(id)
+func (s *userService) GetByID(ctx context.Context, id uint) (*models.User, error) {
+	return s.userRepo.GetByID(ctx, id)
 }
 
-func (s *userService) Update(user *models.User) error {
-	return s.userRepo.Update(user)
+func (s *userService) Update(ctx context.Context, user *models.User) error {
+	return s.userRepo.Update(ctx, user)
 }
 
-func (s *userService) Delete(id uint) error {
-	return s.userRepo.Delete(id)
+func (s *userService) Delete(ctx context.Context, id uint) error {
+	return s.userRepo.Delete(ctx, id)
 }
 
-func (s *userService) List(page, pageSize int) ([]*models.User, int64, error) {
+func (s *userService) List(ctx context.Context, page, pageSize int) ([]*models.User, int64, error) {
 	offset := (page - 1) * pageSize
-	return s.userRepo.List(offset, pageSize)
+	return s.userRepo.List(ctx, offset, pageSize)
+}
+
+func (s *userService) ChangePassword(ctx context.Context, userID uint, oldPassword, newPassword string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(oldPassword)); err != nil {
+		return ErrInvalidCredentials
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	user.Password = string(hashedPassword)
+	return s.userRepo.Update(ctx, user)
 }
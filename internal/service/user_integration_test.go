@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cfrs2005/ppmtest/internal/config"
+	"github.com/cfrs2005/ppmtest/internal/oidc"
+	"github.com/cfrs2005/ppmtest/internal/repository"
+	"github.com/cfrs2005/ppmtest/internal/testutil"
+
+	"gorm.io/gorm"
+)
+
+// These tests exercise UserService against a real UserRepository/database
+// (see internal/testutil) rather than MockUserRepository, so a regression
+// in the repository's SQL - a unique-index violation on Register, say -
+// fails here instead of passing against a mock that doesn't enforce it.
+
+// newTestUserService wires a UserService against db with a default MFA
+// config (no roles required, so ordinary Register/Login tests aren't forced
+// through the MFA flow) and an empty email filter.
+func newTestUserService(db *gorm.DB) UserService {
+	return NewUserService(
+		repository.NewUserRepository(db),
+		repository.NewMFABackupCodeRepository(db),
+		repository.NewUserIdentityRepository(db),
+		config.MFAConfig{Issuer: "Test Blog"},
+		oidc.EmailFilter{},
+	)
+}
+
+func TestUserService_Register_Integration(t *testing.T) {
+	db := testutil.NewDB(t)
+	userService := newTestUserService(db)
+	ctx := context.Background()
+
+	user, err := userService.Register(ctx, "integration-alice", "alice@integration.test", "password123")
+	if err != nil {
+		t.Fatalf("Register() error = %v, want nil", err)
+	}
+	if user.ID == 0 {
+		t.Error("Register() did not populate user.ID")
+	}
+	if user.Password == "password123" {
+		t.Error("Register() stored the plaintext password instead of a bcrypt hash")
+	}
+}
+
+func TestUserService_Register_DuplicateEmail_Integration(t *testing.T) {
+	db := testutil.NewDB(t)
+	userService := newTestUserService(db)
+	ctx := context.Background()
+
+	if _, err := userService.Register(ctx, "bob", "bob@integration.test", "password123"); err != nil {
+		t.Fatalf("Register(first) error = %v, want nil", err)
+	}
+
+	_, err := userService.Register(ctx, "bob2", "bob@integration.test", "password123")
+	if err != repository.ErrUserAlreadyExists {
+		t.Fatalf("Register(duplicate email) error = %v, want ErrUserAlreadyExists", err)
+	}
+}
+
+func TestUserService_Login_Integration(t *testing.T) {
+	db := testutil.NewDB(t)
+	userService := newTestUserService(db)
+	ctx := context.Background()
+
+	registered, err := userService.Register(ctx, "carol", "carol@integration.test", "password123")
+	if err != nil {
+		t.Fatalf("Register() error = %v, want nil", err)
+	}
+
+	loggedIn, err := userService.Login(ctx, "carol@integration.test", "password123")
+	if err != nil {
+		t.Fatalf("Login() error = %v, want nil", err)
+	}
+	if loggedIn.ID != registered.ID {
+		t.Errorf("Login() returned user %d, want %d", loggedIn.ID, registered.ID)
+	}
+
+	if _, err := userService.Login(ctx, "carol@integration.test", "wrong-password"); err != ErrInvalidCredentials {
+		t.Errorf("Login(wrong password) error = %v, want ErrInvalidCredentials", err)
+	}
+}
@@ -0,0 +1,28 @@
+package storage
+
+import "fmt"
+
+// Config selects and parameterizes a Storage backend, mirroring
+// config.SearchConfig's role for the search engine.
+type Config struct {
+	// Backend is "local" (default) or "s3".
+	Backend     string
+	LocalDir    string
+	S3Bucket    string
+	S3Region    string
+	S3Endpoint  string
+	S3AccessKey string
+	S3SecretKey string
+}
+
+// New selects a Storage implementation by cfg.Backend.
+func New(cfg Config) (Storage, error) {
+	switch cfg.Backend {
+	case "", "local":
+		return NewLocalFS(cfg.LocalDir), nil
+	case "s3":
+		return NewS3(cfg.S3Endpoint, cfg.S3Bucket, cfg.S3Region, cfg.S3AccessKey, cfg.S3SecretKey), nil
+	default:
+		return nil, fmt.Errorf("storage: unknown backend %q", cfg.Backend)
+	}
+}
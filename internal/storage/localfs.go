@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// LocalFS saves attachments under a directory on local disk, keyed by the
+// same relative path the caller passed to Save.
+type LocalFS struct {
+	baseDir string
+}
+
+func NewLocalFS(baseDir string) *LocalFS {
+	return &LocalFS{baseDir: baseDir}
+}
+
+func (l *LocalFS) resolve(key string) string {
+	return filepath.Join(l.baseDir, filepath.FromSlash(key))
+}
+
+func (l *LocalFS) Save(ctx context.Context, key string, r io.Reader, size int64) error {
+	path := l.resolve(key)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, r)
+	return err
+}
+
+func (l *LocalFS) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	return os.Open(l.resolve(key))
+}
+
+func (l *LocalFS) Delete(ctx context.Context, key string) error {
+	err := os.Remove(l.resolve(key))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,21 @@
+// Package storage abstracts where uploaded file bytes live behind a single
+// Storage interface, so service.AttachmentService can save/open/delete a
+// key without knowing whether it ends up on local disk or in S3.
+package storage
+
+import (
+	"context"
+	"io"
+)
+
+// Storage saves and serves attachment bytes under an opaque key. Callers
+// choose the key (service.AttachmentService uses a random ID plus the
+// original extension); Storage never inspects it.
+type Storage interface {
+	// Save streams r (exactly size bytes) to key, creating or overwriting it.
+	Save(ctx context.Context, key string, r io.Reader, size int64) error
+	// Open returns a reader for key's contents. Callers must Close it.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes key. Deleting a key that doesn't exist is not an error.
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,27 @@
+package testutil
+
+import (
+	"fmt"
+
+	"github.com/cfrs2005/ppmtest/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// SeedUser inserts and returns a user with sensible defaults, overridable by
+// mutating the returned value before further use. The username/email are
+// suffixed with n so callers can seed several users in one test without
+// tripping the unique indexes on both columns.
+func SeedUser(db *gorm.DB, n int) (*models.User, error) {
+	user := &models.User{
+		Username: fmt.Sprintf("fixture-user-%d", n),
+		Email:    fmt.Sprintf("fixture-user-%d@example.com", n),
+		Password: "$2a$10$fixturefixturefixturefuxturefixturefixturefixturefix",
+		Role:     "author",
+		Status:   "active",
+	}
+	if err := db.Create(user).Error; err != nil {
+		return nil, err
+	}
+	return user, nil
+}
@@ -0,0 +1,79 @@
+package tracing
+
+import "gorm.io/gorm"
+
+// GormPlugin starts a child span around every GORM operation, named after
+// the callback it ran from (gorm:create, gorm:query, ...), using
+// *gorm.DB.Statement.Context - the same context repositories already pass
+// in via .WithContext(ctx) - so a request's trace extends from its HTTP
+// span through its service calls down to each SQL query.
+//
+// gorm.DB.Callback()'s per-operation processor type is unexported, so
+// unlike callbackFor-style helpers in some plugins, each operation is
+// wired individually below rather than through a shared loop.
+type GormPlugin struct{}
+
+// NewGormPlugin returns a GormPlugin; register it with db.Use.
+func NewGormPlugin() *GormPlugin {
+	return &GormPlugin{}
+}
+
+func (p *GormPlugin) Name() string {
+	return "tracing"
+}
+
+func (p *GormPlugin) Initialize(db *gorm.DB) error {
+	before := func(name string) func(tx *gorm.DB) {
+		return func(tx *gorm.DB) {
+			ctx, span := Start(tx.Statement.Context, name)
+			span.SetAttribute("table", tx.Statement.Table)
+			tx.Statement.Context = ctx
+			tx.InstanceSet("tracing:span", span)
+		}
+	}
+	after := func(tx *gorm.DB) {
+		if v, ok := tx.InstanceGet("tracing:span"); ok {
+			if span, ok := v.(*Span); ok {
+				span.Finish()
+			}
+		}
+	}
+
+	if err := db.Callback().Create().Before("gorm:create").Register("tracing:before_create", before("gorm:create")); err != nil {
+		return err
+	}
+	if err := db.Callback().Create().After("gorm:create").Register("tracing:after_create", after); err != nil {
+		return err
+	}
+	if err := db.Callback().Query().Before("gorm:query").Register("tracing:before_query", before("gorm:query")); err != nil {
+		return err
+	}
+	if err := db.Callback().Query().After("gorm:query").Register("tracing:after_query", after); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().Before("gorm:update").Register("tracing:before_update", before("gorm:update")); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().After("gorm:update").Register("tracing:after_update", after); err != nil {
+		return err
+	}
+	if err := db.Callback().Delete().Before("gorm:delete").Register("tracing:before_delete", before("gorm:delete")); err != nil {
+		return err
+	}
+	if err := db.Callback().Delete().After("gorm:delete").Register("tracing:after_delete", after); err != nil {
+		return err
+	}
+	if err := db.Callback().Row().Before("gorm:row").Register("tracing:before_row", before("gorm:row")); err != nil {
+		return err
+	}
+	if err := db.Callback().Row().After("gorm:row").Register("tracing:after_row", after); err != nil {
+		return err
+	}
+	if err := db.Callback().Raw().Before("gorm:raw").Register("tracing:before_raw", before("gorm:raw")); err != nil {
+		return err
+	}
+	if err := db.Callback().Raw().After("gorm:raw").Register("tracing:after_raw", after); err != nil {
+		return err
+	}
+	return nil
+}
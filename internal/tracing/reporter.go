@@ -0,0 +1,111 @@
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cfrs2005/ppmtest/internal/config"
+)
+
+// Reporter is handed every sampled span once it finishes.
+type Reporter interface {
+	Report(span Span)
+}
+
+var (
+	reporterMu     sync.RWMutex
+	reporter       Reporter = logReporter{}
+	sampleRateBits uint64   // atomic store of a float64 via math.Float64bits
+)
+
+func currentReporter() Reporter {
+	reporterMu.RLock()
+	defer reporterMu.RUnlock()
+	return reporter
+}
+
+// Configure applies cfg.Tracing: SampleRate governs shouldSample, and
+// OTLPEndpoint, if set, switches the Reporter from the default
+// logReporter to an httpReporter posting spans there as JSON (not the
+// real OTLP protobuf wire format - see the package doc comment).
+func Configure(cfg config.TracingConfig) {
+	setSampleRate(cfg.SampleRate)
+
+	reporterMu.Lock()
+	defer reporterMu.Unlock()
+	if cfg.OTLPEndpoint != "" {
+		reporter = &httpReporter{endpoint: cfg.OTLPEndpoint, client: &http.Client{Timeout: 5 * time.Second}}
+	} else {
+		reporter = logReporter{}
+	}
+}
+
+func setSampleRate(rate float64) {
+	if rate < 0 {
+		rate = 0
+	}
+	if rate > 1 {
+		rate = 1
+	}
+	atomic.StoreUint64(&sampleRateBits, float64ToBits(rate))
+}
+
+func shouldSample() bool {
+	rate := bitsToFloat64(atomic.LoadUint64(&sampleRateBits))
+	if rate <= 0 {
+		return false
+	}
+	if rate >= 1 {
+		return true
+	}
+	return rand.Float64() < rate
+}
+
+// logReporter writes each sampled span as a structured log line through
+// the default slog logger - internal/logging.New is what actually
+// configures its handler/level/format, so a span shows up formatted the
+// same as every other log line.
+type logReporter struct{}
+
+func (logReporter) Report(span Span) {
+	slog.Info("span",
+		"trace_id", span.TraceID,
+		"span_id", span.SpanID,
+		"parent_span_id", span.ParentSpanID,
+		"name", span.Name,
+		"duration", span.End.Sub(span.Start),
+		"attributes", span.Attributes,
+	)
+}
+
+// httpReporter POSTs each sampled span as a JSON document to endpoint.
+// This is deliberately not the OTLP wire protocol (protobuf over gRPC, or
+// OTLP/HTTP's protobuf-in-HTTP): that's not worth hand-rolling without
+// go.opentelemetry.io/otel vendored. It exists so an operator who points
+// OTLPEndpoint at a small collector that accepts plain JSON still gets
+// these spans out of the process; pointing it at a real OTLP collector
+// will not work without a translator in front of it.
+type httpReporter struct {
+	endpoint string
+	client   *http.Client
+}
+
+func (r *httpReporter) Report(span Span) {
+	body, err := json.Marshal(span)
+	if err != nil {
+		return
+	}
+	// Best-effort: a down collector must never block or fail the request
+	// the span was recorded for.
+	resp, err := r.client.Post(r.endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	resp.Body.Close()
+}
@@ -0,0 +1,30 @@
+package tracing
+
+import "strings"
+
+// traceparentHeader is the W3C Trace Context header name
+// (https://www.w3.org/TR/trace-context/): "version-traceid-parentid-flags".
+const traceparentHeader = "traceparent"
+
+// ParseTraceParent extracts the trace ID and parent span ID from a
+// "traceparent" header value, so a trace started by an upstream caller
+// (OTel-instrumented or not, as long as it speaks this header) continues
+// here instead of starting a new one. Returns ("", "") if header doesn't
+// parse as the expected 4-field form.
+func ParseTraceParent(header string) (traceID, parentSpanID string) {
+	parts := strings.Split(header, "-")
+	if len(parts) != 4 || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return "", ""
+	}
+	return parts[1], parts[2]
+}
+
+// FormatTraceParent renders span as a "traceparent" header value, so a
+// downstream HTTP call this process makes can propagate the trace onward.
+func FormatTraceParent(span *Span) string {
+	flags := "00"
+	if span.Sampled {
+		flags = "01"
+	}
+	return "00-" + span.TraceID + "-" + span.SpanID + "-" + flags
+}
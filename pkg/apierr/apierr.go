@@ -0,0 +1,91 @@
+// Package apierr defines a typed API error so handlers can report failures
+// uniformly instead of each hand-rolling its own status code and JSON body.
+package apierr
+
+import "net/http"
+
+// Code identifies the class of failure, independent of its human-readable
+// message. Clients should switch on Code, not Message.
+type Code string
+
+const (
+	CodeNotFound     Code = "not_found"
+	CodeUnauthorized Code = "unauthorized"
+	CodeForbidden    Code = "forbidden"
+	CodeValidation   Code = "validation"
+	CodeConflict     Code = "conflict"
+	CodeInternal     Code = "internal"
+	CodeRateLimited  Code = "rate_limited"
+	CodeUnavailable  Code = "unavailable"
+)
+
+// APIError is the error type handlers should pass to gin's c.Error. The
+// error-handler middleware registered in router.SetupRouter renders it as a
+// uniform JSON envelope.
+type APIError struct {
+	Code       Code
+	HTTPStatus int
+	Message    string
+	Details    any
+}
+
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+// NotFound builds an APIError for a missing resource.
+func NotFound(message string) *APIError {
+	return &APIError{Code: CodeNotFound, HTTPStatus: http.StatusNotFound, Message: message}
+}
+
+// Unauthorized builds an APIError for a missing or insufficient permission.
+func Unauthorized(message string) *APIError {
+	return &APIError{Code: CodeUnauthorized, HTTPStatus: http.StatusUnauthorized, Message: message}
+}
+
+// Forbidden builds an APIError for an authenticated caller who lacks the
+// permissions needed for the request.
+func Forbidden(message string) *APIError {
+	return &APIError{Code: CodeForbidden, HTTPStatus: http.StatusForbidden, Message: message}
+}
+
+// Validation builds an APIError for invalid request input. details, if
+// non-nil, is rendered alongside Message (e.g. per-field validation errors).
+func Validation(message string, details any) *APIError {
+	return &APIError{Code: CodeValidation, HTTPStatus: http.StatusBadRequest, Message: message, Details: details}
+}
+
+// Conflict builds an APIError for a request that collides with existing
+// state (e.g. a unique-name violation).
+func Conflict(message string) *APIError {
+	return &APIError{Code: CodeConflict, HTTPStatus: http.StatusConflict, Message: message}
+}
+
+// TooManyRequests builds an APIError for a caller that has exceeded a rate
+// limit (e.g. middleware.RateLimitByIP).
+func TooManyRequests(message string) *APIError {
+	return &APIError{Code: CodeRateLimited, HTTPStatus: http.StatusTooManyRequests, Message: message}
+}
+
+// Internal builds an APIError for an unexpected failure. Message is shown to
+// the client, so it must never contain the underlying error's text.
+func Internal(message string) *APIError {
+	return &APIError{Code: CodeInternal, HTTPStatus: http.StatusInternalServerError, Message: message}
+}
+
+// Unavailable builds an APIError for a dependency that's down (e.g. an AI
+// provider plugin that crashed or isn't responding), so the caller gets a
+// structured 503 instead of a generic 500.
+func Unavailable(message string) *APIError {
+	return &APIError{Code: CodeUnavailable, HTTPStatus: http.StatusServiceUnavailable, Message: message}
+}
+
+// From converts any error into an *APIError, defaulting to an opaque
+// internal error when err isn't already one so internal messages never leak
+// to the client.
+func From(err error) *APIError {
+	if apiErr, ok := err.(*APIError); ok {
+		return apiErr
+	}
+	return Internal("an internal error occurred")
+}